@@ -0,0 +1,110 @@
+package dmsg
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/thomasgtaylor/dmsg/internal/markdown"
+)
+
+// AccessibilityWarning describes a component that is hard to use with a
+// screen reader
+type AccessibilityWarning struct {
+	// Path locates the component, e.g. "components[0].components[2]"
+	Path string
+	Msg  string
+}
+
+func (w AccessibilityWarning) String() string {
+	return w.Path + ": " + w.Msg
+}
+
+// CheckAccessibility walks a response and warns about thumbnails and gallery
+// media without descriptions, buttons labelled only with emoji, and markdown
+// headings that skip a level. Unlike Validate, the warnings do not stop
+// Discord from accepting the message.
+func CheckAccessibility(response *discordgo.InteractionResponse) []AccessibilityWarning {
+	if response == nil || response.Data == nil {
+		return nil
+	}
+	a := &accessibilityChecker{}
+	walkComponents(response.Data.Components, a.check)
+	return a.warnings
+}
+
+type accessibilityChecker struct {
+	warnings []AccessibilityWarning
+	heading  int
+}
+
+func (a *accessibilityChecker) warn(path, format string, args ...any) {
+	a.warnings = append(a.warnings, AccessibilityWarning{Path: path, Msg: fmt.Sprintf(format, args...)})
+}
+
+func (a *accessibilityChecker) check(path string, c Component) {
+	switch c := c.(type) {
+	case *discordgo.Thumbnail:
+		if isBlank(c.Description) {
+			a.warn(path, "thumbnail has no description")
+		}
+	case *discordgo.MediaGallery:
+		for i, item := range c.Items {
+			if isBlank(item.Description) {
+				a.warn(fmt.Sprintf("%s.items[%d]", path, i), "media has no description")
+			}
+		}
+	case *discordgo.Button:
+		if c.Style != discordgo.PremiumButton && !hasWords(c.Label) {
+			a.warn(path, "button has no text label; screen readers announce only the emoji name")
+		}
+	case *discordgo.TextDisplay:
+		a.checkHeadings(path, c.Content)
+	}
+}
+
+// checkHeadings warns when a heading is more than one level deeper than the
+// heading before it, across all text displays in order
+func (a *accessibilityChecker) checkHeadings(path, content string) {
+	inFence := false
+	for _, line := range strings.Split(content, "\n") {
+		if markdown.IsFence(line) {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		level := headingLevel(line)
+		if level == 0 {
+			continue
+		}
+		if a.heading > 0 && level > a.heading+1 {
+			a.warn(path, "heading level %d follows level %d", level, a.heading)
+		}
+		a.heading = level
+	}
+}
+
+// headingLevel returns the level of a markdown heading line, or 0
+func headingLevel(line string) int {
+	for level := 1; level <= 3; level++ {
+		if strings.HasPrefix(line, strings.Repeat("#", level)+" ") {
+			return level
+		}
+	}
+	return 0
+}
+
+func isBlank(s *string) bool {
+	return s == nil || strings.TrimSpace(*s) == ""
+}
+
+// hasWords reports whether s contains a letter or digit, so it reads as more
+// than an emoji or symbol
+func hasWords(s string) bool {
+	return strings.IndexFunc(s, func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	}) >= 0
+}
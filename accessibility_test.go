@@ -0,0 +1,103 @@
+package dmsg
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestCheckAccessibility(t *testing.T) {
+	t.Run("accepts described media and labelled buttons", func(t *testing.T) {
+		response := Response(
+			Container(
+				TextDisplay("# Order\n## Items"),
+				Section(TextDisplay("Item"), Accessory(Thumbnail("https://example.com/a.png", "A red mug"))),
+				Gallery(Media("https://example.com/b.png", Description("The mug from above"))),
+				TextDisplay("### Details\n## Shipping"),
+				ActionRow(Button("Buy", "buy", Emoji(&discordgo.ComponentEmoji{Name: "🛒"})), PremiumButton("123")),
+			),
+		)
+
+		if warnings := CheckAccessibility(response); len(warnings) != 0 {
+			t.Errorf("expected no warnings, got %v", warnings)
+		}
+	})
+
+	tests := []struct {
+		name       string
+		components []Component
+		path       string
+		msg        string
+	}{
+		{
+			name:       "thumbnail without description",
+			components: []Component{Section(TextDisplay("Item"), Accessory(Thumbnail("https://example.com/a.png", "")))},
+			path:       "components[0].accessory",
+			msg:        "thumbnail has no description",
+		},
+		{
+			name:       "gallery media without description",
+			components: []Component{Gallery(Media("https://example.com/a.png", Description("A")), Media("https://example.com/b.png"))},
+			path:       "components[0].items[1]",
+			msg:        "media has no description",
+		},
+		{
+			name:       "icon button",
+			components: []Component{ActionRow(IconButton(&discordgo.ComponentEmoji{Name: "🗑️"}, "delete"))},
+			path:       "components[0].components[0]",
+			msg:        "button has no text label; screen readers announce only the emoji name",
+		},
+		{
+			name:       "emoji label",
+			components: []Component{Container(ActionRow(Button("▶️", "play")))},
+			path:       "components[0].components[0].components[0]",
+			msg:        "button has no text label; screen readers announce only the emoji name",
+		},
+		{
+			name:       "skipped heading within a text display",
+			components: []Component{TextDisplay("# Title\n### Details")},
+			path:       "components[0]",
+			msg:        "heading level 3 follows level 1",
+		},
+		{
+			name:       "skipped heading across text displays",
+			components: []Component{TextDisplay("# Title"), Separator(), TextDisplay("```\n## code\n```\n### Details")},
+			path:       "components[2]",
+			msg:        "heading level 3 follows level 1",
+		},
+		{
+			name:       "skipped heading after one-line code block",
+			components: []Component{TextDisplay("```x```\n# A\n### C")},
+			path:       "components[0]",
+			msg:        "heading level 3 follows level 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings := CheckAccessibility(Response(tt.components...))
+			if len(warnings) != 1 {
+				t.Fatalf("expected 1 warning, got %v", warnings)
+			}
+			if warnings[0].Path != tt.path {
+				t.Errorf("expected path %s, got %s", tt.path, warnings[0].Path)
+			}
+			if warnings[0].Msg != tt.msg {
+				t.Errorf("expected message %q, got %q", tt.msg, warnings[0].Msg)
+			}
+		})
+	}
+
+	t.Run("formats warning with path", func(t *testing.T) {
+		w := AccessibilityWarning{Path: "components[0]", Msg: "thumbnail has no description"}
+		if w.String() != "components[0]: thumbnail has no description" {
+			t.Errorf("unexpected string %q", w.String())
+		}
+	})
+
+	t.Run("handles nil response", func(t *testing.T) {
+		if warnings := CheckAccessibility(nil); warnings != nil {
+			t.Errorf("expected no warnings, got %v", warnings)
+		}
+	})
+}
@@ -0,0 +1,152 @@
+package dmsg
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Attachment is a file uploaded with the message and shown by a Thumbnail,
+// File or Gallery through its attachment:// URL. Attachments used by
+// components are added to the payload by Response, Ephemeral, Update,
+// Message and Split automatically.
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// URL returns the attachment:// URL that refers to the attachment
+func (a *Attachment) URL() string {
+	return "attachment://" + a.Name
+}
+
+func (a *Attachment) file(name string) *discordgo.File {
+	return &discordgo.File{
+		Name:        name,
+		ContentType: a.ContentType,
+		Reader:      bytes.NewReader(a.Data),
+	}
+}
+
+type attachOption struct {
+	attachment *Attachment
+}
+
+func (o attachOption) applyToThumbnail(t *discordgo.Thumbnail) {
+	t.Media.URL = o.attachment.URL()
+}
+
+func (o attachOption) applyToFile(f *discordgo.FileComponent) {
+	f.File.URL = o.attachment.URL()
+}
+
+func (o attachOption) applyToMedia(m *MediaItem) {
+	m.Attachment = o.attachment
+}
+
+// Attach shows an uploaded attachment in a Thumbnail, File or Media item,
+// replacing its URL with the attachment's
+func Attach(attachment *Attachment) interface {
+	ThumbnailOption
+	FileOption
+	MediaOption
+} {
+	return attachOption{attachment}
+}
+
+// attachmentOf returns the attachment set by the last Attach in opts, or nil
+func attachmentOf[O any](opts []O) *Attachment {
+	var attachment *Attachment
+	for _, opt := range opts {
+		if o, ok := any(opt).(attachOption); ok {
+			attachment = o.attachment
+		}
+	}
+	return attachment
+}
+
+// attachmentRef is an attachment shown by a component, with the URL field
+// that refers to it
+type attachmentRef struct {
+	attachment *Attachment
+	url        *string
+}
+
+// attachedComponent is implemented by wrappers of components that can show
+// attachments. Wrappers with attachments stay wrapped in the component tree,
+// like labelessButton, so collectFiles can find them.
+type attachedComponent interface {
+	Component
+	attachments() []attachmentRef
+}
+
+// treeComponent returns c as it should be stored in the component tree:
+// unwrapped, unless it carries attachments
+func treeComponent(c Component) Component {
+	if a, ok := c.(attachedComponent); ok && len(a.attachments()) > 0 {
+		return c
+	}
+	return Unwrap(c)
+}
+
+// collectFiles returns the files for every attachment shown in the tree,
+// once per attachment; attachments with the same name and contents, such as
+// one chart shown twice, count as one. A different attachment with the same
+// name as an earlier one is uploaded under a new name, and the components
+// showing it are pointed at that name.
+func collectFiles(components []Component) []*discordgo.File {
+	var files []*discordgo.File
+	names := map[*Attachment]string{}
+	used := map[string]*Attachment{}
+	var collect func(components []Component)
+	collect = func(components []Component) {
+		for _, c := range components {
+			if a, ok := c.(attachedComponent); ok {
+				for _, ref := range a.attachments() {
+					name, ok := names[ref.attachment]
+					if !ok {
+						name = uniqueName(ref.attachment, used)
+						if used[name] == nil {
+							used[name] = ref.attachment
+							files = append(files, ref.attachment.file(name))
+						}
+						names[ref.attachment] = name
+					}
+					*ref.url = "attachment://" + name
+				}
+			}
+			switch c := Unwrap(c).(type) {
+			case *discordgo.Container:
+				collect(c.Components)
+			case *discordgo.Section:
+				collect(c.Components)
+				if c.Accessory != nil {
+					collect([]Component{c.Accessory})
+				}
+			}
+		}
+	}
+	collect(components)
+	return files
+}
+
+// uniqueName returns the name to upload a under: its own name, or its name
+// with a numbered suffix such as "chart-2.png" if a different attachment
+// already uses it
+func uniqueName(a *Attachment, used map[string]*Attachment) string {
+	ext := path.Ext(a.Name)
+	base := strings.TrimSuffix(a.Name, ext)
+	name := a.Name
+	for i := 2; used[name] != nil && !used[name].same(a); i++ {
+		name = fmt.Sprintf("%s-%d%s", base, i, ext)
+	}
+	return name
+}
+
+func (a *Attachment) same(other *Attachment) bool {
+	return a.Name == other.Name && a.ContentType == other.ContentType && bytes.Equal(a.Data, other.Data)
+}
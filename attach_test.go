@@ -0,0 +1,131 @@
+package dmsg
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestAttach(t *testing.T) {
+	logo := &Attachment{Name: "logo.png", ContentType: "image/png", Data: []byte("png")}
+
+	t.Run("points thumbnail at attachment", func(t *testing.T) {
+		thumbnail := Unwrap(Thumbnail("", "Logo", Attach(logo))).(*discordgo.Thumbnail)
+
+		if thumbnail.Media.URL != "attachment://logo.png" {
+			t.Errorf("expected attachment://logo.png, got %s", thumbnail.Media.URL)
+		}
+	})
+
+	t.Run("collects files into response", func(t *testing.T) {
+		response := Response(
+			Container(
+				Section(
+					TextDisplay("Logo"),
+					Accessory(Thumbnail("", "Logo", Attach(logo))),
+				),
+				File("", Attach(&Attachment{Name: "report.csv", Data: []byte("a,b")})),
+			),
+		)
+
+		files := response.Data.Files
+		if len(files) != 2 {
+			t.Fatalf("expected 2 files, got %d", len(files))
+		}
+		if files[0].Name != "logo.png" || files[0].ContentType != "image/png" {
+			t.Errorf("unexpected first file %+v", files[0])
+		}
+		data, _ := io.ReadAll(files[1].Reader)
+		if files[1].Name != "report.csv" || string(data) != "a,b" {
+			t.Errorf("unexpected second file %s %q", files[1].Name, data)
+		}
+	})
+
+	t.Run("collects gallery attachments once", func(t *testing.T) {
+		item := MediaItem{Description: "Logo", Attachment: logo}
+		response := Ephemeral(Container(Gallery(item, item)))
+
+		if len(response.Data.Files) != 1 {
+			t.Fatalf("expected 1 file, got %d", len(response.Data.Files))
+		}
+		gallery := Unwrap(response.Data.Components[0].(*discordgo.Container).Components[0]).(*discordgo.MediaGallery)
+		if gallery.Items[0].Media.URL != "attachment://logo.png" {
+			t.Errorf("expected attachment://logo.png, got %s", gallery.Items[0].Media.URL)
+		}
+	})
+
+	t.Run("renames different attachments with the same name", func(t *testing.T) {
+		other := &Attachment{Name: "logo.png", Data: []byte("other")}
+		response := Response(Container(
+			Section(TextDisplay("Logo"), Accessory(Thumbnail("", "Logo", Attach(logo)))),
+			Gallery(Media("", Attach(other), Description("Other"))),
+		))
+
+		files := response.Data.Files
+		if len(files) != 2 || files[0].Name != "logo.png" || files[1].Name != "logo-2.png" {
+			t.Fatalf("expected logo.png and logo-2.png, got %v", files)
+		}
+		gallery := Unwrap(response.Data.Components[0].(*discordgo.Container).Components[1]).(*discordgo.MediaGallery)
+		if gallery.Items[0].Media.URL != "attachment://logo-2.png" {
+			t.Errorf("expected attachment://logo-2.png, got %s", gallery.Items[0].Media.URL)
+		}
+	})
+
+	t.Run("uploads identical attachments once", func(t *testing.T) {
+		dup := &Attachment{Name: "logo.png", ContentType: "image/png", Data: []byte("png")}
+		response := Response(Gallery(Media("", Attach(logo), Description("A")), Media("", Attach(dup), Description("B"))))
+
+		if len(response.Data.Files) != 1 {
+			t.Errorf("expected 1 file, got %d", len(response.Data.Files))
+		}
+	})
+
+	t.Run("marshals attached components", func(t *testing.T) {
+		data, err := json.Marshal(Response(Container(Section(TextDisplay("Logo"), Accessory(Thumbnail("", "Logo", Attach(logo)))))))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !strings.Contains(string(data), `"accessory":{"media":{"url":"attachment://logo.png"},"description":"Logo","spoiler":false,"type":11}`) {
+			t.Errorf("unexpected JSON %s", data)
+		}
+	})
+
+	t.Run("keeps components unwrapped without attachments", func(t *testing.T) {
+		response := Response(Container(File("https://example.com/a.txt")))
+
+		if _, ok := response.Data.Components[0].(*discordgo.Container).Components[0].(*discordgo.FileComponent); !ok {
+			t.Error("expected *discordgo.FileComponent")
+		}
+	})
+
+	t.Run("files are readable on every response", func(t *testing.T) {
+		thumbnail := Thumbnail("", "Logo", Attach(logo))
+		for range 2 {
+			response := Update(Section(TextDisplay("Logo"), Accessory(thumbnail)))
+			data, _ := io.ReadAll(response.Data.Files[0].Reader)
+			if string(data) != "png" {
+				t.Errorf("expected png, got %q", data)
+			}
+		}
+	})
+
+	t.Run("collects top-level file attachments", func(t *testing.T) {
+		response := Response(File("", Attach(logo)))
+
+		if len(response.Data.Files) != 1 {
+			t.Errorf("expected 1 file, got %d", len(response.Data.Files))
+		}
+	})
+
+	t.Run("leaves files nil without attachments", func(t *testing.T) {
+		response := Response(TextDisplay("Hello"))
+
+		if response.Data.Files != nil {
+			t.Errorf("expected no files, got %d", len(response.Data.Files))
+		}
+	})
+}
@@ -0,0 +1,342 @@
+// Package charts draws simple line and bar charts as PNG images that can be
+// shown in dmsg thumbnails and galleries without hosting them anywhere. The
+// image is uploaded with the message as an attachment.
+//
+//	chart := charts.Line([]float64{3, 5, 4, 8, 7})
+//	dmsg.Response(
+//		dmsg.Container(
+//			dmsg.TextDisplay("## Requests per minute"),
+//			dmsg.Gallery(chart.Media("Requests per minute")),
+//		),
+//	)
+package charts
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+
+	"github.com/thomasgtaylor/dmsg"
+)
+
+type kind int
+
+const (
+	lineChart kind = iota
+	barChart
+)
+
+// Default chart settings, matching Discord's dark theme
+var (
+	DefaultWidth      = 600
+	DefaultHeight     = 300
+	DefaultBackground = color.RGBA{0x2b, 0x2d, 0x31, 0xff}
+	DefaultGrid       = color.RGBA{0x3f, 0x41, 0x47, 0xff}
+	DefaultColors     = []color.Color{
+		color.RGBA{0x58, 0x65, 0xf2, 0xff},
+		color.RGBA{0x57, 0xf2, 0x87, 0xff},
+		color.RGBA{0xfe, 0xe7, 0x5c, 0xff},
+		color.RGBA{0xed, 0x42, 0x45, 0xff},
+		color.RGBA{0xeb, 0x45, 0x9e, 0xff},
+	}
+)
+
+// Option configures a Chart
+type Option interface {
+	applyToChart(*Chart)
+}
+
+type series struct {
+	values []float64
+	color  color.Color
+}
+
+// Chart is a line or bar chart
+type Chart struct {
+	kind          kind
+	series        []series
+	width, height int
+	background    color.Color
+	grid          color.Color
+	name          string
+}
+
+type sizeOption struct {
+	width, height int
+}
+
+func (o sizeOption) applyToChart(c *Chart) {
+	c.width, c.height = o.width, o.height
+}
+
+// Size sets the image size in pixels
+func Size(width, height int) Option {
+	return sizeOption{width, height}
+}
+
+type colorOption struct {
+	color color.Color
+}
+
+func (o colorOption) applyToChart(c *Chart) {
+	c.series[0].color = o.color
+}
+
+// Color sets the color of the first series
+func Color(col color.Color) Option {
+	return colorOption{col}
+}
+
+type seriesOption struct {
+	values []float64
+	color  color.Color
+}
+
+func (o seriesOption) applyToChart(c *Chart) {
+	col := o.color
+	if col == nil {
+		col = DefaultColors[len(c.series)%len(DefaultColors)]
+	}
+	c.series = append(c.series, series{o.values, col})
+}
+
+// Series adds another series of values drawn in col. A nil col picks the
+// next default color.
+func Series(values []float64, col color.Color) Option {
+	return seriesOption{values, col}
+}
+
+type backgroundOption struct {
+	color color.Color
+}
+
+func (o backgroundOption) applyToChart(c *Chart) {
+	c.background = o.color
+}
+
+// Background sets the background color
+func Background(col color.Color) Option {
+	return backgroundOption{col}
+}
+
+type nameOption struct {
+	name string
+}
+
+func (o nameOption) applyToChart(c *Chart) {
+	c.name = o.name
+}
+
+// Name sets the attachment file name. By default the name is derived from
+// the image contents, so identical charts share one upload.
+func Name(name string) Option {
+	return nameOption{name}
+}
+
+// Line creates a line chart of values
+func Line(values []float64, opts ...Option) *Chart {
+	return newChart(lineChart, values, opts)
+}
+
+// Bar creates a bar chart of values
+func Bar(values []float64, opts ...Option) *Chart {
+	return newChart(barChart, values, opts)
+}
+
+func newChart(kind kind, values []float64, opts []Option) *Chart {
+	c := &Chart{
+		kind:       kind,
+		series:     []series{{values, DefaultColors[0]}},
+		width:      DefaultWidth,
+		height:     DefaultHeight,
+		background: DefaultBackground,
+		grid:       DefaultGrid,
+	}
+	for _, opt := range opts {
+		opt.applyToChart(c)
+	}
+	if c.width <= 0 || c.height <= 0 {
+		c.width, c.height = DefaultWidth, DefaultHeight
+	}
+	return c
+}
+
+// Image draws the chart
+func (c *Chart) Image() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, c.width, c.height))
+	fillRect(img, img.Bounds(), c.background)
+
+	const padding = 16
+	plot := image.Rect(padding, padding, c.width-padding, c.height-padding)
+	if plot.Empty() {
+		return img
+	}
+	for i := 0; i <= 4; i++ {
+		y := plot.Max.Y - 1 - i*(plot.Dy()-1)/4
+		fillRect(img, image.Rect(plot.Min.X, y, plot.Max.X, y+1), c.grid)
+	}
+
+	lo, hi := c.bounds()
+	scale := func(v float64) int {
+		return plot.Max.Y - 1 - int(math.Round((v-lo)/(hi-lo)*float64(plot.Dy()-1)))
+	}
+	switch c.kind {
+	case barChart:
+		c.drawBars(img, plot, scale)
+	default:
+		c.drawLines(img, plot, scale)
+	}
+	return img
+}
+
+// bounds returns the value range of the y axis, which always includes zero
+func (c *Chart) bounds() (lo, hi float64) {
+	for _, s := range c.series {
+		for _, v := range s.values {
+			if !math.IsNaN(v) && !math.IsInf(v, 0) {
+				lo, hi = math.Min(lo, v), math.Max(hi, v)
+			}
+		}
+	}
+	if lo == hi {
+		hi = lo + 1
+	}
+	return lo, hi
+}
+
+func (c *Chart) drawLines(img *image.RGBA, plot image.Rectangle, scale func(float64) int) {
+	for _, s := range c.series {
+		step := 0.0
+		if len(s.values) > 1 {
+			step = float64(plot.Dx()-1) / float64(len(s.values)-1)
+		}
+		var prev image.Point
+		havePrev := false
+		for i, v := range s.values {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				havePrev = false
+				continue
+			}
+			p := image.Pt(plot.Min.X+int(math.Round(float64(i)*step)), scale(v))
+			if havePrev {
+				drawLine(img, prev, p, s.color)
+			} else {
+				drawLine(img, p, p, s.color)
+			}
+			prev, havePrev = p, true
+		}
+	}
+}
+
+func (c *Chart) drawBars(img *image.RGBA, plot image.Rectangle, scale func(float64) int) {
+	groups := 0
+	for _, s := range c.series {
+		groups = max(groups, len(s.values))
+	}
+	if groups == 0 {
+		return
+	}
+	groupWidth := float64(plot.Dx()) / float64(groups)
+	barWidth := groupWidth * 0.8 / float64(len(c.series))
+	zero := scale(0)
+	for i, s := range c.series {
+		for j, v := range s.values {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				continue
+			}
+			x0 := plot.Min.X + int(math.Round(float64(j)*groupWidth+groupWidth*0.1+float64(i)*barWidth))
+			x1 := max(x0+1, plot.Min.X+int(math.Round(float64(j)*groupWidth+groupWidth*0.1+float64(i+1)*barWidth)))
+			y := scale(v)
+			fillRect(img, image.Rect(x0, min(y, zero), x1, max(y, zero)+1), s.color)
+		}
+	}
+}
+
+// PNG encodes the chart as a PNG image
+func (c *Chart) PNG() []byte {
+	var buf bytes.Buffer
+	// Encoding an in-memory RGBA image to a buffer cannot fail.
+	_ = png.Encode(&buf, c.Image())
+	return buf.Bytes()
+}
+
+// Attachment encodes the chart as a PNG attachment
+func (c *Chart) Attachment() *dmsg.Attachment {
+	data := c.PNG()
+	name := c.name
+	if name == "" {
+		sum := sha256.Sum256(data)
+		name = "chart-" + hex.EncodeToString(sum[:8]) + ".png"
+	}
+	return &dmsg.Attachment{
+		Name:        name,
+		ContentType: "image/png",
+		Data:        data,
+	}
+}
+
+// Media creates a gallery item showing the chart
+func (c *Chart) Media(description string) dmsg.MediaItem {
+	return dmsg.Media("", dmsg.Attach(c.Attachment()), dmsg.Description(description))
+}
+
+// Thumbnail creates a thumbnail showing the chart
+func (c *Chart) Thumbnail(description string, opts ...dmsg.ThumbnailOption) dmsg.Component {
+	return dmsg.Thumbnail("", description, append([]dmsg.ThumbnailOption{dmsg.Attach(c.Attachment())}, opts...)...)
+}
+
+// File creates a file component offering the chart for download
+func (c *Chart) File(opts ...dmsg.FileOption) interface {
+	dmsg.Component
+	dmsg.ContainerOption
+} {
+	return dmsg.File("", append([]dmsg.FileOption{dmsg.Attach(c.Attachment())}, opts...)...)
+}
+
+func fillRect(img *image.RGBA, r image.Rectangle, col color.Color) {
+	r = r.Intersect(img.Bounds())
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.Set(x, y, col)
+		}
+	}
+}
+
+// drawLine draws a two pixel wide line from a to b
+func drawLine(img *image.RGBA, a, b image.Point, col color.Color) {
+	dx, dy := abs(b.X-a.X), -abs(b.Y-a.Y)
+	sx, sy := 1, 1
+	if a.X > b.X {
+		sx = -1
+	}
+	if a.Y > b.Y {
+		sy = -1
+	}
+	err := dx + dy
+	for {
+		fillRect(img, image.Rect(a.X, a.Y, a.X+2, a.Y+2), col)
+		if a == b {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			a.X += sx
+		}
+		if e2 <= dx {
+			err += dx
+			a.Y += sy
+		}
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
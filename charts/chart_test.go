@@ -0,0 +1,111 @@
+package charts
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/thomasgtaylor/dmsg"
+)
+
+func rgba(c color.Color) color.RGBA {
+	return color.RGBAModel.Convert(c).(color.RGBA)
+}
+
+func TestLine(t *testing.T) {
+	t.Run("encodes png of requested size", func(t *testing.T) {
+		img, err := png.Decode(bytes.NewReader(Line([]float64{1, 2, 3}, Size(200, 100)).PNG()))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if img.Bounds() != image.Rect(0, 0, 200, 100) {
+			t.Errorf("expected 200x100, got %v", img.Bounds())
+		}
+	})
+
+	t.Run("draws series in its color", func(t *testing.T) {
+		red := color.RGBA{0xff, 0, 0, 0xff}
+		img := Line([]float64{5, 5}, Size(100, 50), Color(red)).Image()
+
+		// A flat series at the maximum runs along the top of the plot area.
+		if got := rgba(img.At(50, 16)); got != red {
+			t.Errorf("expected %v, got %v", red, got)
+		}
+		if got := rgba(img.At(2, 2)); got != DefaultBackground {
+			t.Errorf("expected background %v, got %v", DefaultBackground, got)
+		}
+	})
+
+	t.Run("draws extra series", func(t *testing.T) {
+		green := color.RGBA{0, 0xff, 0, 0xff}
+		img := Line([]float64{1, 1}, Size(100, 50), Series([]float64{0, 0}, green)).Image()
+
+		if got := rgba(img.At(50, 33)); got != green {
+			t.Errorf("expected %v, got %v", green, got)
+		}
+	})
+}
+
+func TestBar(t *testing.T) {
+	img := Bar([]float64{1, 0}, Size(100, 50)).Image()
+
+	if got := rgba(img.At(30, 28)); got != DefaultColors[0] {
+		t.Errorf("expected bar color %v, got %v", DefaultColors[0], got)
+	}
+	if got := rgba(img.At(70, 28)); got != DefaultBackground {
+		t.Errorf("expected background %v, got %v", DefaultBackground, got)
+	}
+}
+
+func TestAttachment(t *testing.T) {
+	t.Run("derives name from contents", func(t *testing.T) {
+		a := Line([]float64{1, 2}).Attachment()
+		b := Line([]float64{1, 2}).Attachment()
+		c := Line([]float64{2, 1}).Attachment()
+
+		if !strings.HasPrefix(a.Name, "chart-") || !strings.HasSuffix(a.Name, ".png") {
+			t.Errorf("unexpected name %s", a.Name)
+		}
+		if a.Name != b.Name || a.Name == c.Name {
+			t.Errorf("expected names to follow contents, got %s, %s, %s", a.Name, b.Name, c.Name)
+		}
+		if a.ContentType != "image/png" {
+			t.Errorf("expected image/png, got %s", a.ContentType)
+		}
+	})
+
+	t.Run("uses name option", func(t *testing.T) {
+		a := Bar([]float64{1}, Name("usage.png")).Attachment()
+
+		if a.Name != "usage.png" {
+			t.Errorf("expected usage.png, got %s", a.Name)
+		}
+	})
+
+	t.Run("is uploaded with the response", func(t *testing.T) {
+		chart := Line([]float64{1, 2, 3}, Name("trend.png"))
+		response := dmsg.Response(
+			dmsg.Container(
+				dmsg.Section(
+					dmsg.TextDisplay("Trend"),
+					dmsg.Accessory(chart.Thumbnail("Trend")),
+				),
+				dmsg.Gallery(chart.Media("Trend")),
+			),
+		)
+
+		if len(response.Data.Files) != 1 || response.Data.Files[0].Name != "trend.png" {
+			t.Fatalf("expected trend.png to be uploaded once, got %d files", len(response.Data.Files))
+		}
+		container := response.Data.Components[0].(*discordgo.Container)
+		accessory := container.Components[0].(*discordgo.Section).Accessory
+		thumbnail := dmsg.Unwrap(accessory).(*discordgo.Thumbnail)
+		if thumbnail.Media.URL != "attachment://trend.png" {
+			t.Errorf("expected attachment://trend.png, got %s", thumbnail.Media.URL)
+		}
+	})
+}
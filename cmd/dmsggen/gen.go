@@ -0,0 +1,275 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// config controls the shape of the generated file
+type config struct {
+	pkg  string
+	fn   string
+	kind string // "", "response", "ephemeral" or "update"
+}
+
+// payload is the accepted input: either a bare component array or a message
+// object with components and flags
+type payload struct {
+	Components []json.RawMessage
+	Flags      discordgo.MessageFlags
+}
+
+func (p *payload) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		return json.Unmarshal(data, &p.Components)
+	}
+	var v struct {
+		Components []json.RawMessage      `json:"components"`
+		Flags      discordgo.MessageFlags `json:"flags"`
+		Data       *payload               `json:"data"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	// Accept full interaction responses, which nest the message in "data".
+	if v.Data != nil {
+		*p = *v.Data
+		return nil
+	}
+	p.Components, p.Flags = v.Components, v.Flags
+	return nil
+}
+
+// generate converts Components v2 JSON into formatted Go source
+func generate(src []byte, cfg config) ([]byte, error) {
+	var p payload
+	if err := json.Unmarshal(src, &p); err != nil {
+		return nil, fmt.Errorf("parsing input: %w", err)
+	}
+
+	entry := "Response"
+	switch {
+	case cfg.kind == "ephemeral" || cfg.kind == "" && p.Flags&discordgo.MessageFlagsEphemeral != 0:
+		entry = "Ephemeral"
+	case cfg.kind == "update":
+		entry = "Update"
+	}
+
+	g := &generator{}
+	fmt.Fprintf(&g.buf, "package %s\n\n", cfg.pkg)
+	g.buf.WriteString("import (\n\t\"github.com/bwmarrin/discordgo\"\n\t\"github.com/thomasgtaylor/dmsg\"\n)\n\n")
+	fmt.Fprintf(&g.buf, "func %s() *discordgo.InteractionResponse {\n\treturn dmsg.%s(\n", cfg.fn, entry)
+	for i, raw := range p.Components {
+		c, err := discordgo.MessageComponentFromJSON(raw)
+		if err != nil {
+			return nil, fmt.Errorf("components[%d]: %w", i, err)
+		}
+		if err := g.component(fmt.Sprintf("components[%d]", i), c); err != nil {
+			return nil, err
+		}
+	}
+	g.buf.WriteString(")\n}\n")
+
+	out, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting output: %w", err)
+	}
+	return out, nil
+}
+
+type generator struct {
+	buf bytes.Buffer
+}
+
+func (g *generator) printf(format string, args ...any) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *generator) component(path string, c discordgo.MessageComponent) error {
+	switch c := c.(type) {
+	case *discordgo.Container:
+		g.printf("dmsg.Container(\n")
+		if c.AccentColor != nil {
+			g.printf("dmsg.AccentColor(0x%06X),\n", *c.AccentColor)
+		}
+		if c.Spoiler {
+			g.printf("dmsg.Spoiler(),\n")
+		}
+		for i, child := range c.Components {
+			childPath := fmt.Sprintf("%s.components[%d]", path, i)
+			if _, nested := child.(*discordgo.Container); nested {
+				return fmt.Errorf("%s: containers cannot be nested", childPath)
+			}
+			if err := g.component(childPath, child); err != nil {
+				return err
+			}
+		}
+		g.printf("),\n")
+	case *discordgo.Section:
+		g.printf("dmsg.Section(\n")
+		for i, child := range c.Components {
+			text, ok := child.(*discordgo.TextDisplay)
+			if !ok {
+				return fmt.Errorf("%s.components[%d]: sections can only contain text displays", path, i)
+			}
+			g.printf("dmsg.TextDisplay(%s),\n", strconv.Quote(text.Content))
+		}
+		if c.Accessory != nil {
+			g.printf("dmsg.Accessory(\n")
+			switch c.Accessory.(type) {
+			case *discordgo.Thumbnail, *discordgo.Button:
+			default:
+				return fmt.Errorf("%s.accessory: unsupported accessory %s", path, componentName(c.Accessory))
+			}
+			if err := g.component(path+".accessory", c.Accessory); err != nil {
+				return err
+			}
+			g.printf("),\n")
+		}
+		g.printf("),\n")
+	case *discordgo.TextDisplay:
+		g.printf("dmsg.TextDisplay(%s),\n", strconv.Quote(c.Content))
+	case *discordgo.Thumbnail:
+		description := ""
+		if c.Description != nil {
+			description = *c.Description
+		}
+		g.printf("dmsg.Thumbnail(%s, %s", strconv.Quote(c.Media.URL), strconv.Quote(description))
+		if c.Spoiler {
+			g.printf(", dmsg.Spoiler()")
+		}
+		g.printf("),\n")
+	case *discordgo.Separator:
+		var opts []string
+		if c.Divider != nil && !*c.Divider {
+			opts = append(opts, "dmsg.WithDivider(false)")
+		}
+		if c.Spacing != nil && *c.Spacing == discordgo.SeparatorSpacingSizeLarge {
+			opts = append(opts, "dmsg.Spacing(discordgo.SeparatorSpacingSizeLarge)")
+		}
+		g.printf("dmsg.Separator(%s),\n", strings.Join(opts, ", "))
+	case *discordgo.ActionsRow:
+		g.printf("dmsg.ActionRow(\n")
+		for i, child := range c.Components {
+			childPath := fmt.Sprintf("%s.components[%d]", path, i)
+			if _, ok := child.(*discordgo.Button); !ok {
+				return fmt.Errorf("%s: dmsg action rows only support buttons, got %s", childPath, componentName(child))
+			}
+			if err := g.component(childPath, child); err != nil {
+				return err
+			}
+		}
+		g.printf("),\n")
+	case *discordgo.Button:
+		return g.button(path, c)
+	case *discordgo.MediaGallery:
+		g.printf("dmsg.Gallery(\n")
+		for _, item := range c.Items {
+			g.printf("dmsg.Media(%s", strconv.Quote(item.Media.URL))
+			if item.Description != nil && *item.Description != "" {
+				g.printf(", dmsg.Description(%s)", strconv.Quote(*item.Description))
+			}
+			if item.Spoiler {
+				g.printf(", dmsg.Spoiler()")
+			}
+			g.printf("),\n")
+		}
+		g.printf("),\n")
+	case *discordgo.FileComponent:
+		g.printf("dmsg.File(%s", strconv.Quote(c.File.URL))
+		if c.Spoiler {
+			g.printf(", dmsg.Spoiler()")
+		}
+		g.printf("),\n")
+	default:
+		return fmt.Errorf("%s: unsupported component %s", path, componentName(c))
+	}
+	return nil
+}
+
+var buttonStyles = map[discordgo.ButtonStyle]string{
+	discordgo.SecondaryButton: "dmsg.Secondary",
+	discordgo.SuccessButton:   "dmsg.Success",
+	discordgo.DangerButton:    "dmsg.Danger",
+}
+
+func (g *generator) button(path string, b *discordgo.Button) error {
+	icon := false
+	switch b.Style {
+	case discordgo.LinkButton:
+		g.printf("dmsg.LinkButton(%s, %s", strconv.Quote(b.Label), strconv.Quote(b.URL))
+	case 0, discordgo.PrimaryButton, discordgo.SecondaryButton, discordgo.SuccessButton, discordgo.DangerButton:
+		if b.Label == "" && b.Emoji != nil {
+			icon = true
+			g.printf("dmsg.IconButton(%s, %s", emojiLiteral(b.Emoji), strconv.Quote(b.CustomID))
+		} else {
+			g.printf("dmsg.Button(%s, %s", strconv.Quote(b.Label), strconv.Quote(b.CustomID))
+		}
+		if style, ok := buttonStyles[b.Style]; ok {
+			g.printf(", dmsg.Style(%s)", style)
+		}
+	case discordgo.PremiumButton:
+		g.printf("dmsg.PremiumButton(%s", strconv.Quote(b.SKUID))
+	default:
+		return fmt.Errorf("%s: unsupported button style %d", path, b.Style)
+	}
+	if b.Emoji != nil && !icon {
+		g.printf(", dmsg.Emoji(%s)", emojiLiteral(b.Emoji))
+	}
+	if b.Disabled {
+		g.printf(", dmsg.Disabled()")
+	}
+	g.printf("),\n")
+	return nil
+}
+
+func emojiLiteral(e *discordgo.ComponentEmoji) string {
+	var b bytes.Buffer
+	b.WriteString("&discordgo.ComponentEmoji{")
+	sep := ""
+	if e.Name != "" {
+		fmt.Fprintf(&b, "Name: %s", strconv.Quote(e.Name))
+		sep = ", "
+	}
+	if e.ID != "" {
+		fmt.Fprintf(&b, "%sID: %s", sep, strconv.Quote(e.ID))
+		sep = ", "
+	}
+	if e.Animated {
+		fmt.Fprintf(&b, "%sAnimated: true", sep)
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+var componentNames = map[discordgo.ComponentType]string{
+	discordgo.ActionsRowComponent:            "action row",
+	discordgo.ButtonComponent:                "button",
+	discordgo.SelectMenuComponent:            "select menu",
+	discordgo.TextInputComponent:             "text input",
+	discordgo.UserSelectMenuComponent:        "user select menu",
+	discordgo.RoleSelectMenuComponent:        "role select menu",
+	discordgo.MentionableSelectMenuComponent: "mentionable select menu",
+	discordgo.ChannelSelectMenuComponent:     "channel select menu",
+	discordgo.SectionComponent:               "section",
+	discordgo.TextDisplayComponent:           "text display",
+	discordgo.ThumbnailComponent:             "thumbnail",
+	discordgo.MediaGalleryComponent:          "media gallery",
+	discordgo.FileComponentType:              "file",
+	discordgo.SeparatorComponent:             "separator",
+	discordgo.ContainerComponent:             "container",
+}
+
+func componentName(c discordgo.MessageComponent) string {
+	if name, ok := componentNames[c.Type()]; ok {
+		return name
+	}
+	return fmt.Sprintf("component type %d", c.Type())
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+var defaultConfig = config{pkg: "main", fn: "Message"}
+
+func TestGenerate(t *testing.T) {
+	t.Run("generates idiomatic dmsg calls", func(t *testing.T) {
+		src := `{
+  "flags": 32768,
+  "components": [
+    {
+      "type": 17,
+      "accent_color": 5763719,
+      "components": [
+        {
+          "type": 9,
+          "components": [{"type": 10, "content": "## Success!\nYou won"}],
+          "accessory": {"type": 11, "media": {"url": "https://example.com/t.png"}, "description": "Trophy"}
+        },
+        {"type": 14, "divider": true, "spacing": 2},
+        {
+          "type": 1,
+          "components": [
+            {"type": 2, "style": 3, "label": "Again", "custom_id": "again", "emoji": {"name": "🔁"}},
+            {"type": 2, "style": 5, "label": "Docs", "url": "https://example.com"}
+          ]
+        },
+        {"type": 12, "items": [{"media": {"url": "https://example.com/1.png"}, "description": "One", "spoiler": true}]}
+      ]
+    },
+    {"type": 10, "content": "Bye"}
+  ]
+}`
+
+		out, err := generate([]byte(src), defaultConfig)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := `package main
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/thomasgtaylor/dmsg"
+)
+
+func Message() *discordgo.InteractionResponse {
+	return dmsg.Response(
+		dmsg.Container(
+			dmsg.AccentColor(0x57F287),
+			dmsg.Section(
+				dmsg.TextDisplay("## Success!\nYou won"),
+				dmsg.Accessory(
+					dmsg.Thumbnail("https://example.com/t.png", "Trophy"),
+				),
+			),
+			dmsg.Separator(dmsg.Spacing(discordgo.SeparatorSpacingSizeLarge)),
+			dmsg.ActionRow(
+				dmsg.Button("Again", "again", dmsg.Style(dmsg.Success), dmsg.Emoji(&discordgo.ComponentEmoji{Name: "🔁"})),
+				dmsg.LinkButton("Docs", "https://example.com"),
+			),
+			dmsg.Gallery(
+				dmsg.Media("https://example.com/1.png", dmsg.Description("One"), dmsg.Spoiler()),
+			),
+		),
+		dmsg.TextDisplay("Bye"),
+	)
+}
+`
+		if string(out) != expected {
+			t.Errorf("unexpected output:\n%s", out)
+		}
+	})
+
+	t.Run("accepts bare component arrays", func(t *testing.T) {
+		out, err := generate([]byte(`[{"type": 14, "divider": false}]`), defaultConfig)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !strings.Contains(string(out), "dmsg.Separator(dmsg.WithDivider(false))") {
+			t.Errorf("unexpected output:\n%s", out)
+		}
+	})
+
+	t.Run("uses ephemeral from flags", func(t *testing.T) {
+		out, err := generate([]byte(`{"flags": 32832, "components": []}`), defaultConfig)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !strings.Contains(string(out), "return dmsg.Ephemeral(") {
+			t.Errorf("unexpected output:\n%s", out)
+		}
+	})
+
+	t.Run("accepts interaction responses", func(t *testing.T) {
+		out, err := generate([]byte(`{"type": 7, "data": {"components": [{"type": 10, "content": "hi"}]}}`),
+			config{pkg: "bot", fn: "Hello", kind: "update"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for _, want := range []string{"package bot", "func Hello()", "return dmsg.Update(", `dmsg.TextDisplay("hi")`} {
+			if !strings.Contains(string(out), want) {
+				t.Errorf("expected %q in output:\n%s", want, out)
+			}
+		}
+	})
+
+	t.Run("generates top-level media", func(t *testing.T) {
+		out, err := generate([]byte(`[{"type": 12, "items": [{"media": {"url": "https://example.com/a.png"}}]}, {"type": 13, "file": {"url": "attachment://a.txt"}}]`), defaultConfig)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for _, want := range []string{`dmsg.Media("https://example.com/a.png"),`, `dmsg.File("attachment://a.txt"),`} {
+			if !strings.Contains(string(out), want) {
+				t.Errorf("expected %q in output:\n%s", want, out)
+			}
+		}
+	})
+
+	t.Run("generates icon buttons", func(t *testing.T) {
+		out, err := generate([]byte(`[{"type": 1, "components": [{"type": 2, "style": 2, "custom_id": "prev", "emoji": {"name": "◀"}}]}]`), defaultConfig)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !strings.Contains(string(out), `dmsg.IconButton(&discordgo.ComponentEmoji{Name: "◀"}, "prev", dmsg.Style(dmsg.Secondary)),`) {
+			t.Errorf("unexpected output:\n%s", out)
+		}
+	})
+
+	t.Run("keeps emoji on link buttons without a label", func(t *testing.T) {
+		out, err := generate([]byte(`[{"type": 1, "components": [{"type": 2, "style": 5, "url": "https://x.com", "emoji": {"name": "🔗"}}]}]`), defaultConfig)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !strings.Contains(string(out), `dmsg.LinkButton("", "https://x.com", dmsg.Emoji(&discordgo.ComponentEmoji{Name: "🔗"})),`) {
+			t.Errorf("unexpected output:\n%s", out)
+		}
+	})
+
+	t.Run("generates premium buttons", func(t *testing.T) {
+		out, err := generate([]byte(`[{"type": 1, "components": [{"type": 2, "style": 6, "sku_id": "123"}]}]`), defaultConfig)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !strings.Contains(string(out), `dmsg.PremiumButton("123"),`) {
+			t.Errorf("unexpected output:\n%s", out)
+		}
+	})
+
+	t.Run("reports unsupported components with path", func(t *testing.T) {
+		tests := []struct {
+			name string
+			src  string
+			want string
+		}{
+			{"select menu", `[{"type": 1, "components": [{"type": 3, "custom_id": "x"}]}]`,
+				"components[0].components[0]: dmsg action rows only support buttons, got select menu"},
+			{"invalid json", `[{`, "parsing input"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := generate([]byte(tt.src), defaultConfig)
+				if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+					t.Errorf("expected error starting %q, got %v", tt.want, err)
+				}
+			})
+		}
+	})
+}
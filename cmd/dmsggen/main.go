@@ -0,0 +1,75 @@
+// Command dmsggen converts Discord Components v2 JSON, as exported by visual
+// message builders, into Go source using dmsg.
+//
+// Usage:
+//
+//	dmsggen [flags] [file.json]
+//
+// The input is read from the named file or standard input, and may be a
+// component array, a message object with "components" and "flags", or a full
+// interaction response. The generated function is written to standard output
+// unless -o is given.
+//
+// Flags:
+//
+//	-pkg name     package clause of the generated file (default "main")
+//	-func name    name of the generated function (default "Message")
+//	-kind kind    response, ephemeral or update (default: from message flags)
+//	-o file       write output to file
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	var cfg config
+	var output string
+	flag.StringVar(&cfg.pkg, "pkg", "main", "package clause of the generated file")
+	flag.StringVar(&cfg.fn, "func", "Message", "name of the generated function")
+	flag.StringVar(&cfg.kind, "kind", "", "response, ephemeral or update (default: from message flags)")
+	flag.StringVar(&output, "o", "", "write output to `file`")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: dmsggen [flags] [file.json]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if err := run(cfg, flag.Arg(0), output); err != nil {
+		fmt.Fprintf(os.Stderr, "dmsggen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(cfg config, input, output string) error {
+	switch cfg.kind {
+	case "", "response", "ephemeral", "update":
+	default:
+		return fmt.Errorf("unknown kind %q (expected response, ephemeral or update)", cfg.kind)
+	}
+
+	var src []byte
+	var err error
+	if input == "" || input == "-" {
+		src, err = io.ReadAll(os.Stdin)
+	} else {
+		src, err = os.ReadFile(input)
+	}
+	if err != nil {
+		return err
+	}
+
+	out, err := generate(src, cfg)
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		_, err = os.Stdout.Write(out)
+		return err
+	}
+	return os.WriteFile(output, out, 0o644)
+}
@@ -0,0 +1,16 @@
+// Command dmsgvet checks dmsg component calls for messages Discord would
+// reject. Run it through go vet:
+//
+//	go install github.com/thomasgtaylor/dmsg/cmd/dmsgvet@latest
+//	go vet -vettool=$(which dmsgvet) ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/unitchecker"
+
+	"github.com/thomasgtaylor/dmsg/dmsgvet"
+)
+
+func main() {
+	unitchecker.Main(dmsgvet.Analyzer)
+}
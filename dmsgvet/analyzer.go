@@ -0,0 +1,155 @@
+// Package dmsgvet defines an analyzer that reports dmsg calls Discord would
+// reject: duplicate custom IDs within a message, action rows with too many
+// buttons, link buttons given a custom ID instead of a URL, sections with too
+// many text displays and galleries with too many or no items.
+package dmsgvet
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+const dmsgPath = "github.com/thomasgtaylor/dmsg"
+
+// Discord limits checked by the analyzer, matching the dmsg constants
+const (
+	maxActionRowButtons = 5
+	maxSectionTexts     = 3
+	maxGalleryItems     = 10
+)
+
+// Analyzer reports dmsg calls that build messages Discord would reject
+var Analyzer = &analysis.Analyzer{
+	Name: "dmsgvet",
+	Doc:  "check dmsg component calls for messages Discord would reject",
+	Run:  run,
+}
+
+// customIDArgs maps dmsg functions to the index of their custom ID argument
+var customIDArgs = map[string]int{
+	"Button":     1,
+	"IconButton": 1,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			if call, ok := n.(*ast.CallExpr); ok {
+				checkCall(pass, call)
+			}
+			return true
+		})
+		// Custom IDs must be unique per message, so each outermost dmsg call
+		// is checked as a whole.
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || dmsgFunc(pass, call) == "" {
+				return true
+			}
+			checkCustomIDs(pass, call)
+			return false
+		})
+	}
+	return nil, nil
+}
+
+// dmsgFunc returns the name of the dmsg function called, or "" if call is
+// not a call to a dmsg function
+func dmsgFunc(pass *analysis.Pass, call *ast.CallExpr) string {
+	var ident *ast.Ident
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		ident = fun
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	default:
+		return ""
+	}
+	fn, ok := pass.TypesInfo.Uses[ident].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != dmsgPath {
+		return ""
+	}
+	if sig, ok := fn.Type().(*types.Signature); ok && sig.Recv() != nil {
+		return ""
+	}
+	return fn.Name()
+}
+
+func checkCall(pass *analysis.Pass, call *ast.CallExpr) {
+	// Spread arguments have an unknown length.
+	spread := call.Ellipsis.IsValid()
+	switch dmsgFunc(pass, call) {
+	case "ActionRow":
+		if !spread && len(call.Args) > maxActionRowButtons {
+			pass.Reportf(call.Pos(), "ActionRow has %d buttons; Discord allows at most %d", len(call.Args), maxActionRowButtons)
+		}
+	case "LinkButton":
+		if len(call.Args) > 1 {
+			if url, ok := stringConstant(pass, call.Args[1]); ok && !strings.Contains(url, "://") {
+				pass.Reportf(call.Args[1].Pos(), "LinkButton URL %q has no scheme; use Button for custom IDs", url)
+			}
+		}
+	case "Section":
+		texts := 0
+		for _, arg := range call.Args {
+			if inner, ok := arg.(*ast.CallExpr); ok && dmsgFunc(pass, inner) == "TextDisplay" {
+				texts++
+			}
+		}
+		if texts > maxSectionTexts {
+			pass.Reportf(call.Pos(), "Section has %d text displays; Discord allows at most %d", texts, maxSectionTexts)
+		}
+	case "Gallery":
+		switch {
+		case spread:
+		case len(call.Args) == 0:
+			pass.Reportf(call.Pos(), "Gallery has no items; Discord requires at least one")
+		case len(call.Args) > maxGalleryItems:
+			pass.Reportf(call.Pos(), "Gallery has %d items; Discord allows at most %d", len(call.Args), maxGalleryItems)
+		}
+	}
+}
+
+func checkCustomIDs(pass *analysis.Pass, root *ast.CallExpr) {
+	seen := map[string]token.Pos{}
+	ast.Inspect(root, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		index, ok := customIDArgs[dmsgFunc(pass, call)]
+		if !ok || index >= len(call.Args) {
+			return true
+		}
+		id, ok := stringConstant(pass, call.Args[index])
+		if !ok {
+			return true
+		}
+		if first, dup := seen[id]; dup {
+			pass.Reportf(call.Args[index].Pos(), "duplicate custom ID %q (also used at %s)", id, shortPosition(pass.Fset.Position(first)))
+		} else {
+			seen[id] = call.Args[index].Pos()
+		}
+		return true
+	})
+}
+
+// stringConstant returns the value of expr if it is a constant string
+func stringConstant(pass *analysis.Pass, expr ast.Expr) (string, bool) {
+	tv, ok := pass.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+func shortPosition(p token.Position) string {
+	name := p.Filename[strings.LastIndexAny(p.Filename, `/\`)+1:]
+	return fmt.Sprintf("%s:%d:%d", name, p.Line, p.Column)
+}
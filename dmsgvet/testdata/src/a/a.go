@@ -0,0 +1,64 @@
+package a
+
+import "github.com/thomasgtaylor/dmsg"
+
+const confirmID = "confirm"
+
+func duplicates() {
+	dmsg.Response(
+		dmsg.ActionRow(
+			dmsg.Button("Yes", confirmID),
+			dmsg.Button("No", "cancel"),
+		),
+		dmsg.ActionRow(
+			dmsg.Button("Sure", "confirm"), // want `duplicate custom ID "confirm" \(also used at a.go:10:23\)`
+		),
+	)
+
+	dmsg.Response(dmsg.ActionRow(
+		dmsg.IconButton(nil, "close"),
+		dmsg.Button("Close", "close"), // want `duplicate custom ID "close"`
+	))
+
+	// Separate messages may reuse custom IDs.
+	dmsg.Response(dmsg.ActionRow(dmsg.Button("Yes", "confirm")))
+}
+
+func dynamicIDs(ids []string) {
+	for _, id := range ids {
+		dmsg.Response(dmsg.ActionRow(dmsg.Button("A", id), dmsg.Button("B", id)))
+	}
+}
+
+func rows(buttons []dmsg.Component) {
+	dmsg.ActionRow( // want `ActionRow has 6 buttons; Discord allows at most 5`
+		dmsg.Button("1", "1"),
+		dmsg.Button("2", "2"),
+		dmsg.Button("3", "3"),
+		dmsg.Button("4", "4"),
+		dmsg.Button("5", "5"),
+		dmsg.Button("6", "6"),
+	)
+	dmsg.ActionRow(buttons...)
+}
+
+func links() {
+	dmsg.LinkButton("Docs", "https://example.com")
+	dmsg.LinkButton("Docs", "open_docs") // want `LinkButton URL "open_docs" has no scheme; use Button for custom IDs`
+}
+
+func sections() {
+	dmsg.Section( // want `Section has 4 text displays; Discord allows at most 3`
+		dmsg.TextDisplay("1"),
+		dmsg.TextDisplay("2"),
+		dmsg.TextDisplay("3"),
+		dmsg.TextDisplay("4"),
+	)
+}
+
+func galleries(items []dmsg.MediaItem) {
+	m := dmsg.Media("https://example.com/a.png")
+	dmsg.Gallery()                                // want `Gallery has no items; Discord requires at least one`
+	dmsg.Gallery(m, m, m, m, m, m, m, m, m, m, m) // want `Gallery has 11 items; Discord allows at most 10`
+	dmsg.Gallery(items...)
+}
@@ -0,0 +1,17 @@
+// Package dmsg is a stub of the dmsg API for analyzer tests.
+package dmsg
+
+type Component interface{}
+
+type MediaItem struct{}
+
+func Response(components ...Component) Component                         { return nil }
+func Container(opts ...Component) Component                              { return nil }
+func Section(opts ...Component) Component                                { return nil }
+func TextDisplay(content string) Component                               { return nil }
+func ActionRow(buttons ...Component) Component                           { return nil }
+func Button(label, customID string, opts ...Component) Component         { return nil }
+func IconButton(emoji any, customID string, opts ...Component) Component { return nil }
+func LinkButton(label, url string, opts ...Component) Component          { return nil }
+func Media(url string, opts ...any) MediaItem                            { return MediaItem{} }
+func Gallery(items ...MediaItem) Component                               { return nil }
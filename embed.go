@@ -0,0 +1,142 @@
+package dmsg
+
+import (
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// LegacyEmbed converts a container into an embed and v1 action rows, for
+// webhooks and bridges that do not understand Components v2. The accent
+// color becomes the embed color, the first heading becomes the title, the
+// first thumbnail accessory becomes the embed thumbnail and the first gallery
+// image becomes the embed image. Remaining text is joined into the
+// description and buttons are gathered into action rows. Embeds show a
+// single image, so further gallery items are dropped.
+//
+// Attachments shown by the embed's thumbnail or image, or by file
+// components, are returned as files to upload with the embed.
+func LegacyEmbed(container Component) (*discordgo.MessageEmbed, []discordgo.MessageComponent, []*discordgo.File) {
+	files := collectFiles([]Component{container})
+	container = Unwrap(container)
+	components := []Component{container}
+	embed := &discordgo.MessageEmbed{}
+	if c, ok := container.(*discordgo.Container); ok {
+		components = c.Components
+		if c.AccentColor != nil {
+			embed.Color = *c.AccentColor
+		}
+	}
+
+	e := &embedBuilder{embed: embed}
+	e.add(components)
+	embed.Description = TruncateMarkdown(strings.Join(e.paragraphs, "\n\n"), MaxEmbedDescriptionLength)
+	if len(e.buttons) > 0 {
+		e.rows = append(e.rows, buttonRows(e.buttons)...)
+	}
+	if embed.Thumbnail != nil {
+		e.uses(embed.Thumbnail.URL)
+	}
+	if embed.Image != nil {
+		e.uses(embed.Image.URL)
+	}
+	var used []*discordgo.File
+	for _, f := range files {
+		if e.attachments["attachment://"+f.Name] {
+			used = append(used, f)
+		}
+	}
+	return embed, e.rows, used
+}
+
+type embedBuilder struct {
+	embed       *discordgo.MessageEmbed
+	paragraphs  []string
+	buttons     []discordgo.MessageComponent
+	rows        []discordgo.MessageComponent
+	attachments map[string]bool
+}
+
+// uses records that the embed shows url, so an attachment behind it is
+// uploaded
+func (e *embedBuilder) uses(url string) {
+	if e.attachments == nil {
+		e.attachments = map[string]bool{}
+	}
+	e.attachments[url] = true
+}
+
+func (e *embedBuilder) add(components []Component) {
+	for _, c := range components {
+		switch c := Unwrap(c).(type) {
+		case *discordgo.Container:
+			e.add(c.Components)
+		case *discordgo.Section:
+			e.add(c.Components)
+			if c.Accessory != nil {
+				e.add([]Component{c.Accessory})
+			}
+		case *discordgo.TextDisplay:
+			e.addText(c.Content)
+		case *discordgo.Thumbnail:
+			if e.embed.Thumbnail == nil {
+				e.embed.Thumbnail = &discordgo.MessageEmbedThumbnail{URL: c.Media.URL}
+			}
+		case *discordgo.MediaGallery:
+			if e.embed.Image == nil && len(c.Items) > 0 {
+				e.embed.Image = &discordgo.MessageEmbedImage{URL: c.Items[0].Media.URL}
+			}
+		case *discordgo.FileComponent:
+			// Uploaded files appear with the message, and links to them do
+			// not work in embeds.
+			if strings.HasPrefix(c.File.URL, "attachment://") {
+				e.uses(c.File.URL)
+				continue
+			}
+			name := c.File.URL[strings.LastIndex(c.File.URL, "/")+1:]
+			e.paragraphs = append(e.paragraphs, "["+name+"]("+c.File.URL+")")
+		case *discordgo.ActionsRow:
+			e.rows = append(e.rows, c)
+		default:
+			if _, ok := asButton(c); ok {
+				e.buttons = append(e.buttons, c)
+			}
+		}
+	}
+}
+
+// addText adds a text display to the description, taking its first line as
+// the title when it is a heading and no title has been set
+func (e *embedBuilder) addText(content string) {
+	if e.embed.Title == "" {
+		first, rest, _ := strings.Cut(content, "\n")
+		if title, ok := headingText(first); ok {
+			e.embed.Title = TruncateMarkdown(title, MaxEmbedTitleLength)
+			content = strings.TrimLeft(rest, "\n")
+		}
+	}
+	if strings.TrimSpace(content) != "" {
+		e.paragraphs = append(e.paragraphs, content)
+	}
+}
+
+// headingText returns the text of a markdown heading line
+func headingText(line string) (string, bool) {
+	for _, prefix := range []string{"# ", "## ", "### "} {
+		if strings.HasPrefix(line, prefix) {
+			return strings.TrimSpace(line[len(prefix):]), true
+		}
+	}
+	return "", false
+}
+
+// buttonRows groups buttons into action rows of MaxActionRowButtons
+func buttonRows(buttons []discordgo.MessageComponent) []discordgo.MessageComponent {
+	var rows []discordgo.MessageComponent
+	for len(buttons) > 0 {
+		n := min(len(buttons), MaxActionRowButtons)
+		rows = append(rows, &discordgo.ActionsRow{Components: buttons[:n]})
+		buttons = buttons[n:]
+	}
+	return rows
+}
@@ -0,0 +1,120 @@
+package dmsg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestLegacyEmbed(t *testing.T) {
+	t.Run("converts container", func(t *testing.T) {
+		embed, rows, _ := LegacyEmbed(Container(
+			AccentColor(0x5865F2),
+			Section(
+				TextDisplay("## Release 1.2\nNow with charts."),
+				Accessory(Thumbnail("https://example.com/logo.png", "Logo")),
+			),
+			Separator(),
+			TextDisplay("See the changelog for details."),
+			Gallery(
+				Media("https://example.com/one.png", Description("One")),
+				Media("https://example.com/two.png", Description("Two")),
+			),
+			ActionRow(LinkButton("Changelog", "https://example.com/changelog")),
+		))
+
+		if embed.Color != 0x5865F2 {
+			t.Errorf("expected color 0x5865F2, got %#x", embed.Color)
+		}
+		if embed.Title != "Release 1.2" {
+			t.Errorf("expected title Release 1.2, got %q", embed.Title)
+		}
+		if embed.Description != "Now with charts.\n\nSee the changelog for details." {
+			t.Errorf("unexpected description %q", embed.Description)
+		}
+		if embed.Thumbnail == nil || embed.Thumbnail.URL != "https://example.com/logo.png" {
+			t.Errorf("unexpected thumbnail %+v", embed.Thumbnail)
+		}
+		if embed.Image == nil || embed.Image.URL != "https://example.com/one.png" {
+			t.Errorf("unexpected image %+v", embed.Image)
+		}
+		if len(rows) != 1 {
+			t.Fatalf("expected 1 row, got %d", len(rows))
+		}
+		if _, ok := rows[0].(*discordgo.ActionsRow); !ok {
+			t.Error("expected *discordgo.ActionsRow")
+		}
+	})
+
+	t.Run("keeps later headings in description", func(t *testing.T) {
+		embed, _, _ := LegacyEmbed(Container(
+			TextDisplay("# Title"),
+			TextDisplay("## Subtitle\nBody"),
+		))
+
+		if embed.Title != "Title" {
+			t.Errorf("expected title Title, got %q", embed.Title)
+		}
+		if embed.Description != "## Subtitle\nBody" {
+			t.Errorf("unexpected description %q", embed.Description)
+		}
+	})
+
+	t.Run("gathers button accessories into rows", func(t *testing.T) {
+		var sections []ContainerOption
+		for i := range 6 {
+			sections = append(sections, Section(
+				TextDisplay("Item"),
+				Accessory(Button("Buy", "buy_"+string(rune('a'+i)))),
+			))
+		}
+		_, rows, _ := LegacyEmbed(Container(sections...))
+
+		if len(rows) != 2 {
+			t.Fatalf("expected 2 rows, got %d", len(rows))
+		}
+		if n := len(rows[0].(*discordgo.ActionsRow).Components); n != 5 {
+			t.Errorf("expected 5 buttons in first row, got %d", n)
+		}
+	})
+
+	t.Run("truncates long description", func(t *testing.T) {
+		embed, _, _ := LegacyEmbed(Container(
+			TextDisplay(strings.Repeat("a", 3000)),
+			TextDisplay(strings.Repeat("b", 3000)),
+		))
+
+		if n := len([]rune(embed.Description)); n > MaxEmbedDescriptionLength {
+			t.Errorf("expected at most %d characters, got %d", MaxEmbedDescriptionLength, n)
+		}
+	})
+
+	t.Run("accepts components outside a container", func(t *testing.T) {
+		embed, _, _ := LegacyEmbed(TextDisplay("# Hello"))
+
+		if embed.Title != "Hello" {
+			t.Errorf("expected title Hello, got %q", embed.Title)
+		}
+	})
+	t.Run("returns attachments shown by the embed", func(t *testing.T) {
+		logo := &Attachment{Name: "logo.png", Data: []byte("logo")}
+		extra := &Attachment{Name: "extra.png", Data: []byte("extra")}
+		report := &Attachment{Name: "report.csv", Data: []byte("a,b")}
+		embed, _, files := LegacyEmbed(Container(
+			Section(TextDisplay("# Report"), Accessory(Thumbnail("", "Logo", Attach(logo)))),
+			Gallery(Media("https://example.com/a.png", Description("A")), Media("", Attach(extra), Description("Extra"))),
+			File("", Attach(report)),
+		))
+
+		if embed.Thumbnail.URL != "attachment://logo.png" {
+			t.Errorf("expected attachment://logo.png, got %s", embed.Thumbnail.URL)
+		}
+		if len(files) != 2 || files[0].Name != "logo.png" || files[1].Name != "report.csv" {
+			t.Errorf("expected logo.png and report.csv, got %v", files)
+		}
+		if strings.Contains(embed.Description, "attachment://") {
+			t.Errorf("unexpected attachment link in %q", embed.Description)
+		}
+	})
+}
@@ -0,0 +1,400 @@
+package dmsg
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var customEmojiPattern = regexp.MustCompile(`^<(a?):([A-Za-z0-9_~]{2,32}):([0-9]+)>$`)
+
+// ParseEmoji parses an emoji as written in Discord messages: a custom emoji
+// such as "<:name:id>" or "<a:name:id>" for animated ones, a unicode emoji
+// such as "🔥", or a shortcode such as ":fire:" for common unicode emoji.
+func ParseEmoji(s string) (*discordgo.ComponentEmoji, error) {
+	s = strings.TrimSpace(s)
+	if m := customEmojiPattern.FindStringSubmatch(s); m != nil {
+		return &discordgo.ComponentEmoji{Name: m[2], ID: m[3], Animated: m[1] == "a"}, nil
+	}
+	if name, ok := shortcodeName(s); ok {
+		if emoji, ok := shortcodes[strings.ToLower(name)]; ok {
+			return &discordgo.ComponentEmoji{Name: emoji}, nil
+		}
+		return nil, fmt.Errorf("dmsg: unknown emoji shortcode %q", s)
+	}
+	if isUnicodeEmoji(s) {
+		return &discordgo.ComponentEmoji{Name: s}, nil
+	}
+	return nil, fmt.Errorf("dmsg: %q is not an emoji", s)
+}
+
+// MustParseEmoji is like ParseEmoji but panics on error. It is intended for
+// constant emoji in package-level variables and tests.
+func MustParseEmoji(s string) *discordgo.ComponentEmoji {
+	emoji, err := ParseEmoji(s)
+	if err != nil {
+		panic(err)
+	}
+	return emoji
+}
+
+// shortcodeName returns the name inside a ":name:" shortcode
+func shortcodeName(s string) (string, bool) {
+	if len(s) < 3 || s[0] != ':' || s[len(s)-1] != ':' {
+		return "", false
+	}
+	return s[1 : len(s)-1], true
+}
+
+// isUnicodeEmoji reports whether s is a single unicode emoji: an emoji
+// character with an optional variation selector and skin tone, a keycap such
+// as "1️⃣", a flag, or several of these joined into one emoji with zero-width
+// joiners
+func isUnicodeEmoji(s string) bool {
+	runes := []rune(s)
+	switch {
+	case len(runes) == 0:
+		return false
+	case strings.ContainsRune("#*0123456789", runes[0]):
+		rest := runes[1:]
+		if len(rest) > 0 && rest[0] == variationSelector {
+			rest = rest[1:]
+		}
+		return len(rest) == 1 && rest[0] == keycap
+	case isRegionalIndicator(runes[0]):
+		return len(runes) == 2 && isRegionalIndicator(runes[1])
+	}
+	for i := 0; ; i++ {
+		if i >= len(runes) || !isEmojiRune(runes[i]) {
+			return false
+		}
+		i++
+		if i < len(runes) && runes[i] == variationSelector {
+			i++
+		}
+		if i < len(runes) && runes[i] >= 0x1F3FB && runes[i] <= 0x1F3FF {
+			i++
+		}
+		// Tags spell out subdivision flags such as England's.
+		for i < len(runes) && runes[i] >= 0xE0020 && runes[i] <= 0xE007F {
+			i++
+		}
+		if i == len(runes) {
+			return true
+		}
+		if runes[i] != zeroWidthJoiner {
+			return false
+		}
+	}
+}
+
+const (
+	variationSelector = '\uFE0F'
+	zeroWidthJoiner   = '\u200D'
+	keycap            = '\u20E3'
+)
+
+// emojiRanges holds the blocks emoji are drawn from
+var emojiRanges = [][2]rune{
+	{0x00A9, 0x00A9}, {0x00AE, 0x00AE}, {0x203C, 0x203C}, {0x2049, 0x2049},
+	{0x2122, 0x2122}, {0x2139, 0x2139}, {0x2194, 0x21FF}, {0x2300, 0x23FF},
+	{0x24C2, 0x24C2}, {0x25A0, 0x25FF}, {0x2600, 0x27BF}, {0x2934, 0x2935},
+	{0x2B00, 0x2BFF}, {0x3030, 0x3030}, {0x303D, 0x303D}, {0x3297, 0x3297},
+	{0x3299, 0x3299}, {0x1F000, 0x1FAFF},
+}
+
+func isEmojiRune(r rune) bool {
+	for _, rng := range emojiRanges {
+		if r >= rng[0] && r <= rng[1] {
+			return true
+		}
+	}
+	return false
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+// EmojiRegistry looks up custom emoji by name, so buttons can use a guild's
+// or application's emoji without hard-coding IDs. It is safe for concurrent
+// use.
+type EmojiRegistry struct {
+	mu     sync.RWMutex
+	emojis map[string]discordgo.ComponentEmoji
+}
+
+// NewEmojiRegistry creates a registry holding emojis
+func NewEmojiRegistry(emojis ...*discordgo.Emoji) *EmojiRegistry {
+	r := &EmojiRegistry{emojis: map[string]discordgo.ComponentEmoji{}}
+	r.Add(emojis...)
+	return r
+}
+
+// Add adds emojis to the registry, replacing any with the same name
+func (r *EmojiRegistry) Add(emojis ...*discordgo.Emoji) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range emojis {
+		r.emojis[e.Name] = discordgo.ComponentEmoji{Name: e.Name, ID: e.ID, Animated: e.Animated}
+	}
+}
+
+// LoadGuild adds the emojis of a guild
+func (r *EmojiRegistry) LoadGuild(s *discordgo.Session, guildID string) error {
+	emojis, err := s.GuildEmojis(guildID)
+	if err != nil {
+		return err
+	}
+	r.Add(emojis...)
+	return nil
+}
+
+// LoadApplication adds the emojis uploaded to an application
+func (r *EmojiRegistry) LoadApplication(s *discordgo.Session, appID string) error {
+	emojis, err := s.ApplicationEmojis(appID)
+	if err != nil {
+		return err
+	}
+	r.Add(emojis...)
+	return nil
+}
+
+// Get returns the registered emoji with the given name
+func (r *EmojiRegistry) Get(name string) (*discordgo.ComponentEmoji, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	emoji, ok := r.emojis[name]
+	if !ok {
+		return nil, false
+	}
+	return &emoji, true
+}
+
+// Parse is like ParseEmoji, but resolves "name" and ":name:" to registered
+// emojis first
+func (r *EmojiRegistry) Parse(s string) (*discordgo.ComponentEmoji, error) {
+	name := strings.TrimSpace(s)
+	if n, ok := shortcodeName(name); ok {
+		name = n
+	}
+	if emoji, ok := r.Get(name); ok {
+		return emoji, nil
+	}
+	return ParseEmoji(s)
+}
+
+type registryEmojiOption struct {
+	registry *EmojiRegistry
+	name     string
+}
+
+func (o registryEmojiOption) applyToButton(b *discordgo.Button) {
+	if emoji, err := o.registry.Parse(o.name); err == nil {
+		b.Emoji = emoji
+	}
+}
+
+// Emoji sets the button emoji to the named registered emoji, or to the emoji
+// ParseEmoji returns for name. The button is left without an emoji if name
+// matches neither; use Parse to check.
+func (r *EmojiRegistry) Emoji(name string) ButtonOption {
+	return registryEmojiOption{r, name}
+}
+
+// shortcodes maps common shortcodes to unicode emoji
+var shortcodes = map[string]string{
+	"+1":                         "👍",
+	"-1":                         "👎",
+	"thumbsup":                   "👍",
+	"thumbsdown":                 "👎",
+	"ok_hand":                    "👌",
+	"wave":                       "👋",
+	"clap":                       "👏",
+	"pray":                       "🙏",
+	"muscle":                     "💪",
+	"point_right":                "👉",
+	"point_left":                 "👈",
+	"point_up":                   "☝️",
+	"point_down":                 "👇",
+	"eyes":                       "👀",
+	"smile":                      "😄",
+	"grin":                       "😁",
+	"joy":                        "😂",
+	"laughing":                   "😆",
+	"wink":                       "😉",
+	"blush":                      "😊",
+	"heart_eyes":                 "😍",
+	"sunglasses":                 "😎",
+	"thinking":                   "🤔",
+	"neutral_face":               "😐",
+	"confused":                   "😕",
+	"cry":                        "😢",
+	"sob":                        "😭",
+	"angry":                      "😠",
+	"rage":                       "😡",
+	"scream":                     "😱",
+	"sweat_smile":                "😅",
+	"upside_down":                "🙃",
+	"partying_face":              "🥳",
+	"skull":                      "💀",
+	"ghost":                      "👻",
+	"robot":                      "🤖",
+	"heart":                      "❤️",
+	"orange_heart":               "🧡",
+	"yellow_heart":               "💛",
+	"green_heart":                "💚",
+	"blue_heart":                 "💙",
+	"purple_heart":               "💜",
+	"black_heart":                "🖤",
+	"broken_heart":               "💔",
+	"sparkles":                   "✨",
+	"star":                       "⭐",
+	"star2":                      "🌟",
+	"fire":                       "🔥",
+	"zap":                        "⚡",
+	"boom":                       "💥",
+	"tada":                       "🎉",
+	"confetti_ball":              "🎊",
+	"gift":                       "🎁",
+	"trophy":                     "🏆",
+	"medal":                      "🏅",
+	"first_place":                "🥇",
+	"second_place":               "🥈",
+	"third_place":                "🥉",
+	"crown":                      "👑",
+	"gem":                        "💎",
+	"moneybag":                   "💰",
+	"coin":                       "🪙",
+	"rocket":                     "🚀",
+	"hourglass":                  "⌛",
+	"stopwatch":                  "⏱️",
+	"alarm_clock":                "⏰",
+	"calendar":                   "📆",
+	"bell":                       "🔔",
+	"no_bell":                    "🔕",
+	"mega":                       "📣",
+	"loudspeaker":                "📢",
+	"speech_balloon":             "💬",
+	"mag":                        "🔍",
+	"lock":                       "🔒",
+	"unlock":                     "🔓",
+	"key":                        "🔑",
+	"shield":                     "🛡️",
+	"hammer":                     "🔨",
+	"wrench":                     "🔧",
+	"gear":                       "⚙️",
+	"link":                       "🔗",
+	"paperclip":                  "📎",
+	"pushpin":                    "📌",
+	"memo":                       "📝",
+	"pencil2":                    "✏️",
+	"book":                       "📖",
+	"books":                      "📚",
+	"bookmark":                   "🔖",
+	"clipboard":                  "📋",
+	"file_folder":                "📁",
+	"package":                    "📦",
+	"inbox_tray":                 "📥",
+	"outbox_tray":                "📤",
+	"envelope":                   "✉️",
+	"bar_chart":                  "📊",
+	"chart_with_upwards_trend":   "📈",
+	"chart_with_downwards_trend": "📉",
+	"bulb":                       "💡",
+	"information_source":         "ℹ️",
+	"warning":                    "⚠️",
+	"no_entry":                   "⛔",
+	"no_entry_sign":              "🚫",
+	"x":                          "❌",
+	"heavy_check_mark":           "✔️",
+	"white_check_mark":           "✅",
+	"ballot_box_with_check":      "☑️",
+	"question":                   "❓",
+	"exclamation":                "❗",
+	"bangbang":                   "‼️",
+	"heavy_plus_sign":            "➕",
+	"heavy_minus_sign":           "➖",
+	"heavy_multiplication_x":     "✖️",
+	"arrow_left":                 "⬅️",
+	"arrow_right":                "➡️",
+	"arrow_up":                   "⬆️",
+	"arrow_down":                 "⬇️",
+	"arrows_counterclockwise":    "🔄",
+	"repeat":                     "🔁",
+	"rewind":                     "⏪",
+	"fast_forward":               "⏩",
+	"previous_track":             "⏮️",
+	"next_track":                 "⏭️",
+	"arrow_backward":             "◀️",
+	"arrow_forward":              "▶️",
+	"pause_button":               "⏸️",
+	"stop_button":                "⏹️",
+	"record_button":              "⏺️",
+	"red_circle":                 "🔴",
+	"orange_circle":              "🟠",
+	"yellow_circle":              "🟡",
+	"green_circle":               "🟢",
+	"blue_circle":                "🔵",
+	"purple_circle":              "🟣",
+	"white_circle":               "⚪",
+	"black_circle":               "⚫",
+	"red_square":                 "🟥",
+	"green_square":               "🟩",
+	"black_large_square":         "⬛",
+	"white_large_square":         "⬜",
+	"one":                        "1️⃣",
+	"two":                        "2️⃣",
+	"three":                      "3️⃣",
+	"four":                       "4️⃣",
+	"five":                       "5️⃣",
+	"six":                        "6️⃣",
+	"seven":                      "7️⃣",
+	"eight":                      "8️⃣",
+	"nine":                       "9️⃣",
+	"keycap_ten":                 "🔟",
+	"game_die":                   "🎲",
+	"video_game":                 "🎮",
+	"dart":                       "🎯",
+	"musical_note":               "🎵",
+	"notes":                      "🎶",
+	"art":                        "🎨",
+	"camera":                     "📷",
+	"computer":                   "💻",
+	"desktop":                    "🖥️",
+	"iphone":                     "📱",
+	"globe_with_meridians":       "🌐",
+	"earth_americas":             "🌎",
+	"sunny":                      "☀️",
+	"cloud":                      "☁️",
+	"umbrella":                   "☔",
+	"snowflake":                  "❄️",
+	"rainbow":                    "🌈",
+	"coffee":                     "☕",
+	"pizza":                      "🍕",
+	"cake":                       "🍰",
+	"cookie":                     "🍪",
+	"beers":                      "🍻",
+	"cat":                        "🐱",
+	"dog":                        "🐶",
+	"bug":                        "🐛",
+	"seedling":                   "🌱",
+	"herb":                       "🌿",
+	"four_leaf_clover":           "🍀",
+	"hundred":                    "💯",
+	"100":                        "💯",
+	"zzz":                        "💤",
+	"wastebasket":                "🗑️",
+	"hourglass_flowing_sand":     "⏳",
+	"stop_sign":                  "🛑",
+	"construction":               "🚧",
+	"triangular_flag_on_post":    "🚩",
+	"checkered_flag":             "🏁",
+	"new":                        "🆕",
+	"free":                       "🆓",
+	"sos":                        "🆘",
+	"id":                         "🆔",
+}
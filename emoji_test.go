@@ -0,0 +1,121 @@
+package dmsg
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestParseEmoji(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want discordgo.ComponentEmoji
+	}{
+		{"custom", "<:party:123456789012345678>", discordgo.ComponentEmoji{Name: "party", ID: "123456789012345678"}},
+		{"animated", "<a:dance:42>", discordgo.ComponentEmoji{Name: "dance", ID: "42", Animated: true}},
+		{"unicode", "🔥", discordgo.ComponentEmoji{Name: "🔥"}},
+		{"unicode with variation selector", "▶️", discordgo.ComponentEmoji{Name: "▶️"}},
+		{"keycap", "1️⃣", discordgo.ComponentEmoji{Name: "1️⃣"}},
+		{"shortcode", ":fire:", discordgo.ComponentEmoji{Name: "🔥"}},
+		{"shortcode ignores case", ":White_Check_Mark:", discordgo.ComponentEmoji{Name: "✅"}},
+		{"trims spaces", " ✖ ", discordgo.ComponentEmoji{Name: "✖"}},
+		{"skin tone", "👍🏽", discordgo.ComponentEmoji{Name: "👍🏽"}},
+		{"zero-width joiner sequence", "👩‍💻", discordgo.ComponentEmoji{Name: "👩‍💻"}},
+		{"flag", "🇳🇱", discordgo.ComponentEmoji{Name: "🇳🇱"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseEmoji(tt.in)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if *got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, *got)
+			}
+		})
+	}
+
+	for _, in := range []string{"", "fire", ":not_an_emoji:", "<:bad:>", "two words", "日本", "€", "🔥🔥🔥", "1", "1⃣⃣", "🇳", "👩‍", "♥x"} {
+		t.Run("rejects "+in, func(t *testing.T) {
+			if _, err := ParseEmoji(in); err == nil {
+				t.Errorf("expected error for %q", in)
+			}
+		})
+	}
+}
+
+func TestMustParseEmoji(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic")
+		}
+	}()
+	MustParseEmoji("fire")
+}
+
+func TestEmojiRegistry(t *testing.T) {
+	registry := NewEmojiRegistry(
+		&discordgo.Emoji{Name: "coin", ID: "1"},
+		&discordgo.Emoji{Name: "spin", ID: "2", Animated: true},
+	)
+
+	t.Run("gets emoji by name", func(t *testing.T) {
+		emoji, ok := registry.Get("spin")
+		if !ok {
+			t.Fatal("expected spin to be registered")
+		}
+
+		if emoji.ID != "2" || !emoji.Animated {
+			t.Errorf("unexpected emoji %+v", emoji)
+		}
+	})
+
+	t.Run("parses registered names before shortcodes", func(t *testing.T) {
+		registry := NewEmojiRegistry(&discordgo.Emoji{Name: "fire", ID: "3"})
+
+		emoji, err := registry.Parse(":fire:")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if emoji.ID != "3" {
+			t.Errorf("expected registered fire emoji, got %+v", emoji)
+		}
+	})
+
+	t.Run("falls back to ParseEmoji", func(t *testing.T) {
+		emoji, err := registry.Parse(":tada:")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if emoji.Name != "🎉" {
+			t.Errorf("expected 🎉, got %s", emoji.Name)
+		}
+	})
+
+	t.Run("returns copies", func(t *testing.T) {
+		emoji, _ := registry.Get("coin")
+		emoji.Name = "changed"
+
+		if again, _ := registry.Get("coin"); again.Name != "coin" {
+			t.Errorf("expected registry to be unchanged, got %s", again.Name)
+		}
+	})
+
+	t.Run("sets button emoji", func(t *testing.T) {
+		button := Button("Pay", "pay", registry.Emoji("coin")).(*discordgo.Button)
+
+		if button.Emoji == nil || button.Emoji.ID != "1" {
+			t.Errorf("unexpected emoji %+v", button.Emoji)
+		}
+	})
+
+	t.Run("leaves unknown button emoji unset", func(t *testing.T) {
+		button := Button("Pay", "pay", registry.Emoji("missing")).(*discordgo.Button)
+
+		if button.Emoji != nil {
+			t.Errorf("expected no emoji, got %+v", button.Emoji)
+		}
+	})
+}
@@ -0,0 +1,74 @@
+package dmsg
+
+import "strings"
+
+// FieldItem is a name/value pair laid out like a classic embed field
+type FieldItem struct {
+	Name   string
+	Value  string
+	Inline bool
+}
+
+// Field creates a field shown on its own rows
+func Field(name, value string) FieldItem {
+	return FieldItem{
+		Name:  name,
+		Value: value,
+	}
+}
+
+// InlineField creates a field shown side by side with neighbouring inline
+// fields, up to three per row
+func InlineField(name, value string) FieldItem {
+	return FieldItem{
+		Name:   name,
+		Value:  value,
+		Inline: true,
+	}
+}
+
+// maxInlineFields is the number of inline fields grouped on one row, as in
+// embeds
+const maxInlineFields = 3
+
+// inlineFieldSeparator separates inline fields on a row
+const inlineFieldSeparator = "  ·  "
+
+// Fields lays out name/value pairs like embed fields, as text displays. Each
+// field is a bold name above its value; consecutive inline fields are grouped
+// up to three per row.
+func Fields(fields ...FieldItem) Components {
+	var components Components
+	var row []FieldItem
+	flushRow := func() {
+		if len(row) > 0 {
+			components = append(components, TextDisplay(inlineRow(row)))
+			row = nil
+		}
+	}
+	for _, f := range fields {
+		if !f.Inline {
+			flushRow()
+			components = append(components, TextDisplay("**"+f.Name+"**\n"+f.Value))
+			continue
+		}
+		row = append(row, f)
+		if len(row) == maxInlineFields {
+			flushRow()
+		}
+	}
+	flushRow()
+	return components
+}
+
+// inlineRow renders inline fields as a line of names above a line of values.
+// Values are flattened to one line so the columns stay paired.
+func inlineRow(fields []FieldItem) string {
+	names := make([]string, len(fields))
+	values := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = "**" + f.Name + "**"
+		values[i] = strings.Join(strings.Fields(f.Value), " ")
+	}
+	return strings.Join(names, inlineFieldSeparator) + "\n" + strings.Join(values, inlineFieldSeparator)
+}
@@ -0,0 +1,84 @@
+package dmsg
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestFields(t *testing.T) {
+	contents := func(components Components) []string {
+		out := make([]string, len(components))
+		for i, c := range components {
+			out[i] = c.(textDisplayComponent).Content
+		}
+		return out
+	}
+
+	t.Run("renders block fields", func(t *testing.T) {
+		got := contents(Fields(Field("Level", "12"), Field("Class", "Mage")))
+
+		expected := []string{"**Level**\n12", "**Class**\nMage"}
+		if len(got) != len(expected) || got[0] != expected[0] || got[1] != expected[1] {
+			t.Errorf("expected %q, got %q", expected, got)
+		}
+	})
+
+	t.Run("groups inline fields three per row", func(t *testing.T) {
+		got := contents(Fields(
+			InlineField("HP", "100"),
+			InlineField("MP", "50"),
+			InlineField("XP", "7"),
+			InlineField("Gold", "3"),
+		))
+
+		expected := []string{
+			"**HP**  ·  **MP**  ·  **XP**\n100  ·  50  ·  7",
+			"**Gold**\n3",
+		}
+		if len(got) != 2 || got[0] != expected[0] || got[1] != expected[1] {
+			t.Errorf("expected %q, got %q", expected, got)
+		}
+	})
+
+	t.Run("block field ends inline row", func(t *testing.T) {
+		got := contents(Fields(
+			InlineField("A", "1"),
+			Field("B", "2"),
+			InlineField("C", "3"),
+		))
+
+		if len(got) != 3 {
+			t.Errorf("expected 3 text displays, got %q", got)
+		}
+	})
+
+	t.Run("flattens multi-line inline values", func(t *testing.T) {
+		got := contents(Fields(InlineField("Notes", "line one\nline two")))
+
+		if got[0] != "**Notes**\nline one line two" {
+			t.Errorf("unexpected content %q", got[0])
+		}
+	})
+
+	t.Run("works in containers", func(t *testing.T) {
+		container := Container(Fields(Field("A", "1"), Field("B", "2")))
+
+		c := container.(*discordgo.Container)
+		if len(c.Components) != 2 {
+			t.Fatalf("expected 2 components, got %d", len(c.Components))
+		}
+
+		if _, ok := c.Components[0].(*discordgo.TextDisplay); !ok {
+			t.Error("expected *discordgo.TextDisplay")
+		}
+	})
+
+	t.Run("works as top-level components", func(t *testing.T) {
+		response := Response(Fields(Field("A", "1"))...)
+
+		if _, ok := response.Data.Components[0].(*discordgo.TextDisplay); !ok {
+			t.Error("expected *discordgo.TextDisplay")
+		}
+	})
+}
@@ -0,0 +1,13 @@
+// Package markdown holds Discord markdown helpers shared by dmsg and its
+// subpackages.
+package markdown
+
+import "strings"
+
+// IsFence reports whether line opens or closes a fenced code block. A line
+// such as "```code```" closes its own fence on the same line, so it is not
+// one.
+func IsFence(line string) bool {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(line), "```")
+	return ok && !strings.Contains(rest, "```")
+}
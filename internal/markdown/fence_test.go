@@ -0,0 +1,24 @@
+package markdown
+
+import "testing"
+
+func TestIsFence(t *testing.T) {
+	tests := []struct {
+		line string
+		want bool
+	}{
+		{"```", true},
+		{"```go", true},
+		{"  ```", true},
+		{"```inline code```", false},
+		{"``````", false},
+		{"text ```", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsFence(tt.line); got != tt.want {
+			t.Errorf("IsFence(%q): expected %v, got %v", tt.line, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package dmsg
+
+// Discord limits for Components v2 messages
+const (
+	// MaxTextLength is the number of characters allowed across all text
+	// displays in a message
+	MaxTextLength = 4000
+	// MaxComponents is the number of components allowed in a message,
+	// counting nested components
+	MaxComponents = 40
+	// MaxActionRowButtons is the number of buttons allowed in an action row
+	MaxActionRowButtons = 5
+	// MaxSectionTexts is the number of text displays allowed in a section
+	MaxSectionTexts = 3
+	// MaxGalleryItems is the number of media items allowed in a gallery
+	MaxGalleryItems = 10
+	// MaxCustomIDLength is the number of characters allowed in a custom ID
+	MaxCustomIDLength = 100
+	// MaxButtonLabelLength is the number of characters allowed in a button
+	// label
+	MaxButtonLabelLength = 80
+)
+
+// MaxContentLength is the number of characters allowed in the content of a
+// message without Components v2
+const MaxContentLength = 2000
+
+// Discord limits for embeds
+const (
+	// MaxEmbedTitleLength is the number of characters allowed in an embed
+	// title
+	MaxEmbedTitleLength = 256
+	// MaxEmbedDescriptionLength is the number of characters allowed in an
+	// embed description
+	MaxEmbedDescriptionLength = 4096
+)
@@ -0,0 +1,264 @@
+package dmsg
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/thomasgtaylor/dmsg/internal/markdown"
+)
+
+var (
+	imageLinePattern = regexp.MustCompile(`^(?:!\[([^\]]*)\]\(([^)\s]+)\)\s*)+$`)
+	imagePattern     = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)\)`)
+	linkItemPattern  = regexp.MustCompile(`^[-*+] \[([^\]]+)\]\(([^)\s]+)\)$`)
+	rulePattern      = regexp.MustCompile(`^(?:-{3,}|\*{3,}|_{3,})$`)
+)
+
+// FromMarkdown converts a markdown document, such as a help page or
+// changelog, into Components v2 containers.
+//
+// Each level-one heading starts a new container and other headings start a
+// new text display. Horizontal rules become separators, lines of images
+// become galleries, and lists consisting only of links become rows of link
+// buttons. Text is split on paragraph boundaries to fit MaxTextLength.
+// Options, such as AccentColor, are applied to every container.
+//
+// Long documents may need more than one message; see Split.
+func FromMarkdown(src string, opts ...ContainerOption) []Component {
+	c := markdownConverter{opts: opts}
+	inCode := false
+	for _, line := range strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		fence := markdown.IsFence(line)
+		if fence {
+			inCode = !inCode
+		}
+		if inCode || fence {
+			c.text(line)
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			if len(c.media) == 0 && len(c.links) == 0 {
+				c.text(line)
+			}
+		case strings.HasPrefix(trimmed, "# "):
+			c.flushContainer()
+			c.text(trimmed)
+		case strings.HasPrefix(trimmed, "## ") || strings.HasPrefix(trimmed, "### "):
+			c.flushText()
+			c.text(trimmed)
+		case rulePattern.MatchString(trimmed):
+			c.flush()
+			c.children = append(c.children, Separator())
+		case imageLinePattern.MatchString(trimmed):
+			c.flushText()
+			c.flushLinks()
+			for _, m := range imagePattern.FindAllStringSubmatch(trimmed, -1) {
+				if len(c.media) == MaxGalleryItems {
+					c.flushMedia()
+				}
+				c.media = append(c.media, Media(m[2], Description(m[1])))
+			}
+		case linkItemPattern.MatchString(trimmed):
+			c.flushText()
+			c.flushMedia()
+			m := linkItemPattern.FindStringSubmatch(trimmed)
+			c.links = append(c.links, LinkButton(m[1], m[2]))
+		default:
+			c.text(line)
+		}
+	}
+	c.flushContainer()
+	return c.containers
+}
+
+type markdownConverter struct {
+	opts       []ContainerOption
+	containers []Component
+	children   []ContainerOption
+	lines      []string
+	media      []MediaItem
+	links      []Component
+}
+
+func (c *markdownConverter) text(line string) {
+	c.flushMedia()
+	c.flushLinks()
+	c.lines = append(c.lines, line)
+}
+
+func (c *markdownConverter) flushText() {
+	content := strings.Trim(strings.Join(c.lines, "\n"), "\n")
+	c.lines = nil
+	if strings.TrimSpace(content) == "" {
+		return
+	}
+	for _, part := range splitMarkdown(content, MaxTextLength) {
+		c.children = append(c.children, TextDisplay(part))
+	}
+}
+
+func (c *markdownConverter) flushMedia() {
+	if len(c.media) > 0 {
+		c.children = append(c.children, Gallery(c.media...))
+		c.media = nil
+	}
+}
+
+func (c *markdownConverter) flushLinks() {
+	for len(c.links) > 0 {
+		n := min(len(c.links), MaxActionRowButtons)
+		c.children = append(c.children, ActionRow(c.links[:n]...))
+		c.links = c.links[n:]
+	}
+}
+
+func (c *markdownConverter) flush() {
+	c.flushText()
+	c.flushMedia()
+	c.flushLinks()
+}
+
+func (c *markdownConverter) flushContainer() {
+	c.flush()
+	if len(c.children) > 0 {
+		opts := append(append([]ContainerOption{}, c.opts...), c.children...)
+		c.containers = append(c.containers, Container(opts...))
+		c.children = nil
+	}
+}
+
+// splitMarkdown splits text into parts of at most limit characters, breaking
+// between paragraphs where possible. Code blocks are never left open: a code
+// block that has to be split is closed and reopened with the same fence.
+func splitMarkdown(text string, limit int) []string {
+	if utf8.RuneCountInString(text) <= limit {
+		return []string{text}
+	}
+
+	var parts []string
+	var current strings.Builder
+	currentLen := 0
+	flush := func() {
+		if currentLen > 0 {
+			parts = append(parts, current.String())
+			current.Reset()
+			currentLen = 0
+		}
+	}
+	add := func(block string) {
+		n := utf8.RuneCountInString(block)
+		if currentLen > 0 && currentLen+2+n > limit {
+			flush()
+		}
+		if currentLen > 0 {
+			current.WriteString("\n\n")
+			currentLen += 2
+		}
+		current.WriteString(block)
+		currentLen += n
+	}
+
+	for _, block := range markdownBlocks(text) {
+		if utf8.RuneCountInString(block) <= limit {
+			add(block)
+			continue
+		}
+		flush()
+		for _, piece := range splitBlock(block, limit) {
+			add(piece)
+		}
+	}
+	flush()
+	return parts
+}
+
+// markdownBlocks splits text into paragraphs, keeping each code block whole
+func markdownBlocks(text string) []string {
+	var blocks, lines []string
+	inCode := false
+	flush := func() {
+		if len(lines) > 0 {
+			blocks = append(blocks, strings.Join(lines, "\n"))
+			lines = nil
+		}
+	}
+	for _, line := range strings.Split(text, "\n") {
+		if markdown.IsFence(line) {
+			if !inCode {
+				flush()
+			}
+			inCode = !inCode
+			lines = append(lines, line)
+			if !inCode {
+				flush()
+			}
+			continue
+		}
+		if !inCode && strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+		lines = append(lines, line)
+	}
+	flush()
+	return blocks
+}
+
+// splitBlock splits a single oversized paragraph or code block by lines
+func splitBlock(block string, limit int) []string {
+	lines := strings.Split(block, "\n")
+	open, close := "", ""
+	if markdown.IsFence(lines[0]) {
+		open, close = lines[0]+"\n", "\n```"
+		lines = lines[1:]
+		if len(lines) > 0 && markdown.IsFence(lines[len(lines)-1]) {
+			lines = lines[:len(lines)-1]
+		}
+	}
+	budget := limit - utf8.RuneCountInString(open) - utf8.RuneCountInString(close)
+
+	var parts []string
+	var current []string
+	currentLen := 0
+	flush := func() {
+		if len(current) > 0 {
+			parts = append(parts, open+strings.Join(current, "\n")+close)
+			current, currentLen = nil, 0
+		}
+	}
+	for _, line := range lines {
+		for _, piece := range splitLine(line, budget) {
+			n := utf8.RuneCountInString(piece)
+			if len(current) > 0 && currentLen+1+n > budget {
+				flush()
+			}
+			if len(current) > 0 {
+				currentLen++
+			}
+			current = append(current, piece)
+			currentLen += n
+		}
+	}
+	flush()
+	return parts
+}
+
+// splitLine breaks a line longer than limit at the last space that fits,
+// or mid-word if there is none
+func splitLine(line string, limit int) []string {
+	var pieces []string
+	for utf8.RuneCountInString(line) > limit {
+		runes := []rune(line)
+		cut := limit
+		if i := strings.LastIndexAny(string(runes[:limit]), " \t"); i > 0 {
+			cut = utf8.RuneCountInString(string(runes[:limit])[:i])
+		}
+		pieces = append(pieces, strings.TrimRight(string(runes[:cut]), " \t"))
+		line = strings.TrimLeft(string(runes[cut:]), " \t")
+	}
+	return append(pieces, line)
+}
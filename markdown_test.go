@@ -0,0 +1,189 @@
+package dmsg
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestFromMarkdown(t *testing.T) {
+	t.Run("maps document structure to components", func(t *testing.T) {
+		src := `# Changelog
+
+Intro paragraph.
+
+## v1.1
+- Fixed **things**
+
+---
+
+![Screenshot](https://example.com/1.png)
+![Other](https://example.com/2.png)
+
+- [Docs](https://example.com/docs)
+- [Support](https://example.com/support)
+
+# Second
+
+Body`
+
+		components := FromMarkdown(src, AccentColor(123))
+
+		if len(components) != 2 {
+			t.Fatalf("expected 2 containers, got %d", len(components))
+		}
+
+		c := components[0].(*discordgo.Container)
+		if c.AccentColor == nil || *c.AccentColor != 123 {
+			t.Error("expected accent color on container")
+		}
+
+		if len(c.Components) != 5 {
+			t.Fatalf("expected 5 container components, got %d", len(c.Components))
+		}
+
+		intro := c.Components[0].(*discordgo.TextDisplay)
+		if intro.Content != "# Changelog\n\nIntro paragraph." {
+			t.Errorf("unexpected intro %q", intro.Content)
+		}
+
+		version := c.Components[1].(*discordgo.TextDisplay)
+		if version.Content != "## v1.1\n- Fixed **things**" {
+			t.Errorf("unexpected section %q", version.Content)
+		}
+
+		if _, ok := c.Components[2].(*discordgo.Separator); !ok {
+			t.Error("expected separator")
+		}
+
+		gallery := c.Components[3].(*discordgo.MediaGallery)
+		if len(gallery.Items) != 2 || *gallery.Items[0].Description != "Screenshot" {
+			t.Errorf("unexpected gallery %+v", gallery)
+		}
+
+		row := c.Components[4].(*discordgo.ActionsRow)
+		if len(row.Components) != 2 {
+			t.Fatalf("expected 2 link buttons, got %d", len(row.Components))
+		}
+
+		if btn := row.Components[0].(*discordgo.Button); btn.Style != discordgo.LinkButton || btn.URL != "https://example.com/docs" {
+			t.Errorf("unexpected link button %+v", btn)
+		}
+
+		second := components[1].(*discordgo.Container)
+		if got := second.Components[0].(*discordgo.TextDisplay).Content; got != "# Second\n\nBody" {
+			t.Errorf("unexpected second container text %q", got)
+		}
+	})
+
+	t.Run("wraps content without heading", func(t *testing.T) {
+		components := FromMarkdown("Just text")
+
+		if len(components) != 1 {
+			t.Fatalf("expected 1 container, got %d", len(components))
+		}
+	})
+
+	t.Run("keeps markdown inside code blocks", func(t *testing.T) {
+		components := FromMarkdown("```\n# not a heading\n---\n```")
+
+		c := components[0].(*discordgo.Container)
+		if len(c.Components) != 1 {
+			t.Fatalf("expected 1 component, got %d", len(c.Components))
+		}
+	})
+
+	t.Run("treats one-line code blocks as text", func(t *testing.T) {
+		components := FromMarkdown("# One\n```inline```\n# Two\nbody\n---\n- [Docs](https://x.com)")
+
+		if len(components) != 2 {
+			t.Fatalf("expected 2 containers, got %d", len(components))
+		}
+		c := components[1].(*discordgo.Container)
+		if len(c.Components) != 3 {
+			t.Fatalf("expected 3 components, got %d", len(c.Components))
+		}
+		if _, ok := c.Components[1].(*discordgo.Separator); !ok {
+			t.Errorf("expected separator, got %T", c.Components[1])
+		}
+		if _, ok := c.Components[2].(*discordgo.ActionsRow); !ok {
+			t.Errorf("expected action row, got %T", c.Components[2])
+		}
+	})
+
+	t.Run("splits long link lists into rows", func(t *testing.T) {
+		var b strings.Builder
+		for range 7 {
+			b.WriteString("- [Link](https://example.com)\n")
+		}
+
+		c := FromMarkdown(b.String())[0].(*discordgo.Container)
+		if len(c.Components) != 2 {
+			t.Fatalf("expected 2 action rows, got %d", len(c.Components))
+		}
+	})
+
+	t.Run("splits long text", func(t *testing.T) {
+		paragraph := strings.Repeat("word ", 300)
+		src := strings.Repeat(paragraph+"\n\n", 5)
+
+		c := FromMarkdown(src)[0].(*discordgo.Container)
+		if len(c.Components) < 2 {
+			t.Fatalf("expected text to be split, got %d components", len(c.Components))
+		}
+
+		for _, child := range c.Components {
+			if n := utf8.RuneCountInString(child.(*discordgo.TextDisplay).Content); n > MaxTextLength {
+				t.Errorf("text display has %d characters", n)
+			}
+		}
+	})
+}
+
+func TestSplitMarkdown(t *testing.T) {
+	t.Run("returns short text unchanged", func(t *testing.T) {
+		parts := splitMarkdown("hello", 10)
+
+		if len(parts) != 1 || parts[0] != "hello" {
+			t.Errorf("unexpected parts %q", parts)
+		}
+	})
+
+	t.Run("splits between paragraphs", func(t *testing.T) {
+		parts := splitMarkdown("aaaa\n\nbbbb\n\ncccc", 10)
+
+		expected := []string{"aaaa\n\nbbbb", "cccc"}
+		if strings.Join(parts, "|") != strings.Join(expected, "|") {
+			t.Errorf("expected %q, got %q", expected, parts)
+		}
+	})
+
+	t.Run("closes and reopens code blocks", func(t *testing.T) {
+		parts := splitMarkdown("```go\nline one\nline two\nline three\n```", 30)
+
+		if len(parts) < 2 {
+			t.Fatalf("expected code block to be split, got %q", parts)
+		}
+
+		for _, part := range parts {
+			if !strings.HasPrefix(part, "```go\n") || !strings.HasSuffix(part, "\n```") {
+				t.Errorf("expected fenced part, got %q", part)
+			}
+
+			if n := utf8.RuneCountInString(part); n > 30 {
+				t.Errorf("part has %d characters", n)
+			}
+		}
+	})
+
+	t.Run("breaks long lines at spaces", func(t *testing.T) {
+		parts := splitMarkdown("aaa bbb ccc ddd", 8)
+
+		expected := []string{"aaa bbb", "ccc ddd"}
+		if strings.Join(parts, "|") != strings.Join(expected, "|") {
+			t.Errorf("expected %q, got %q", expected, parts)
+		}
+	})
+}
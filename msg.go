@@ -9,9 +9,7 @@
 //	response := dmsg.Response(
 //	    dmsg.Container(
 //	        dmsg.AccentColor(5763719),
-//	        dmsg.Section(
-//	            dmsg.Text("## Hello World"),
-//	        ),
+//	        dmsg.TextDisplay("## Hello World"),
 //	    ),
 //	)
 //
@@ -19,7 +17,11 @@
 // sections, buttons, thumbnails, galleries, and more.
 package dmsg
 
-import "github.com/bwmarrin/discordgo"
+import (
+	"encoding/json"
+
+	"github.com/bwmarrin/discordgo"
+)
 
 // Component is any Discord message component
 type Component = discordgo.MessageComponent
@@ -32,15 +34,33 @@ type unwrappable interface {
 func unwrapComponents(components []Component) []Component {
 	unwrapped := make([]Component, len(components))
 	for i, c := range components {
-		if u, ok := c.(unwrappable); ok {
-			unwrapped[i] = u.unwrap()
-		} else {
-			unwrapped[i] = c
-		}
+		unwrapped[i] = treeComponent(c)
 	}
 	return unwrapped
 }
 
+// Unwrap returns the discordgo component behind a component created by dmsg,
+// such as the *discordgo.Button behind an IconButton, or c itself. It is for
+// inspecting components; send the original, as some wrappers change how the
+// component is encoded.
+func Unwrap(c Component) Component {
+	if u, ok := c.(unwrappable); ok {
+		return u.unwrap()
+	}
+	if b, ok := asButton(c); ok {
+		return b
+	}
+	return c
+}
+
+// Components is a group of components produced together, such as Fields. It
+// can be added to a container as one option, or spread into Response.
+type Components []Component
+
+func (c Components) applyToContainer(container *discordgo.Container) {
+	container.Components = append(container.Components, unwrapComponents(c)...)
+}
+
 // Response creates a standard interaction response
 func Response(components ...Component) *discordgo.InteractionResponse {
 	return &discordgo.InteractionResponse{
@@ -48,6 +68,7 @@ func Response(components ...Component) *discordgo.InteractionResponse {
 		Data: &discordgo.InteractionResponseData{
 			Flags:      discordgo.MessageFlagsIsComponentsV2,
 			Components: unwrapComponents(components),
+			Files:      collectFiles(components),
 		},
 	}
 }
@@ -59,6 +80,7 @@ func Ephemeral(components ...Component) *discordgo.InteractionResponse {
 		Data: &discordgo.InteractionResponseData{
 			Flags:      discordgo.MessageFlagsIsComponentsV2 | discordgo.MessageFlagsEphemeral,
 			Components: unwrapComponents(components),
+			Files:      collectFiles(components),
 		},
 	}
 }
@@ -70,10 +92,32 @@ func Update(components ...Component) *discordgo.InteractionResponse {
 		Data: &discordgo.InteractionResponseData{
 			Flags:      discordgo.MessageFlagsIsComponentsV2,
 			Components: unwrapComponents(components),
+			Files:      collectFiles(components),
 		},
 	}
 }
 
+// Message creates a channel message, for ChannelMessageSendComplex
+func Message(components ...Component) *discordgo.MessageSend {
+	return &discordgo.MessageSend{
+		Flags:      discordgo.MessageFlagsIsComponentsV2,
+		Components: unwrapComponents(components),
+		Files:      collectFiles(components),
+	}
+}
+
+// interactionResponsePremiumRequired is the response type that shows the
+// premium upsell, which discordgo does not define
+const interactionResponsePremiumRequired discordgo.InteractionResponseType = 10
+
+// PremiumRequired creates a response asking the user to upgrade to premium.
+// Discord has deprecated it in favor of PremiumButton.
+func PremiumRequired() *discordgo.InteractionResponse {
+	return &discordgo.InteractionResponse{
+		Type: interactionResponsePremiumRequired,
+	}
+}
+
 // ContainerOption configures a Container
 type ContainerOption interface {
 	applyToContainer(*discordgo.Container)
@@ -84,7 +128,7 @@ func Container(opts ...ContainerOption) Component {
 	container := &discordgo.Container{
 		Components: []discordgo.MessageComponent{},
 	}
-	for _, opt := range opts {
+	for _, opt := range defaultsFirst(opts) {
 		opt.applyToContainer(container)
 	}
 	return container
@@ -117,11 +161,17 @@ func (o spoilerOption) applyToFile(f *discordgo.FileComponent) {
 	f.Spoiler = true
 }
 
-// Spoiler marks a component as a spoiler (Container, Thumbnail, or File)
+func (o spoilerOption) applyToMedia(m *MediaItem) {
+	m.Spoiler = true
+}
+
+// Spoiler marks a component as a spoiler (Container, Thumbnail, File, or
+// Media)
 func Spoiler() interface {
 	ContainerOption
 	ThumbnailOption
 	FileOption
+	MediaOption
 } {
 	return spoilerOption{}
 }
@@ -202,17 +252,24 @@ func (t textDisplayComponent) applyToContainer(c *discordgo.Container) {
 	c.Components = append(c.Components, t.TextDisplay)
 }
 
+// TextDisplayOption configures a TextDisplay
+type TextDisplayOption interface {
+	applyToTextDisplay(*discordgo.TextDisplay)
+}
+
 // TextDisplay creates a text display component (can be used top-level, in containers, or in sections)
-func TextDisplay(content string) interface {
+func TextDisplay(content string, opts ...TextDisplayOption) interface {
 	Component
 	ContainerOption
 	SectionOption
 } {
-	return textDisplayComponent{
-		&discordgo.TextDisplay{
-			Content: content,
-		},
+	text := &discordgo.TextDisplay{
+		Content: content,
+	}
+	for _, opt := range opts {
+		opt.applyToTextDisplay(text)
 	}
+	return textDisplayComponent{text}
 }
 
 // ThumbnailOption configures a Thumbnail
@@ -220,20 +277,37 @@ type ThumbnailOption interface {
 	applyToThumbnail(*discordgo.Thumbnail)
 }
 
-// Thumbnail creates a thumbnail component
+// Thumbnail creates a thumbnail component. An empty description is omitted.
 func Thumbnail(url, description string, opts ...ThumbnailOption) Component {
 	thumbnail := &discordgo.Thumbnail{
 		Media: discordgo.UnfurledMediaItem{
 			URL: url,
 		},
-		Description: &description,
+		Description: optionalString(description),
 	}
 	for _, opt := range opts {
 		opt.applyToThumbnail(thumbnail)
 	}
+	if attachment := attachmentOf(opts); attachment != nil {
+		return thumbnailComponent{thumbnail, attachment}
+	}
 	return thumbnail
 }
 
+// thumbnailComponent is a thumbnail showing an attachment
+type thumbnailComponent struct {
+	*discordgo.Thumbnail
+	attachment *Attachment
+}
+
+func (t thumbnailComponent) unwrap() Component {
+	return t.Thumbnail
+}
+
+func (t thumbnailComponent) attachments() []attachmentRef {
+	return []attachmentRef{{t.attachment, &t.Media.URL}}
+}
+
 // SeparatorOption configures a Separator
 type SeparatorOption interface {
 	applyToSeparator(*discordgo.Separator)
@@ -262,7 +336,7 @@ func Separator(opts ...SeparatorOption) interface {
 		Divider: &truth,
 		Spacing: &spacing,
 	}
-	for _, opt := range opts {
+	for _, opt := range defaultsFirst(opts) {
 		opt.applyToSeparator(separator)
 	}
 	return separatorComponent{separator}
@@ -326,6 +400,7 @@ const (
 	Secondary ButtonStyle = 2
 	Success   ButtonStyle = 3
 	Danger    ButtonStyle = 4
+	Premium   ButtonStyle = 6
 )
 
 // ButtonOption configures a Button
@@ -340,7 +415,7 @@ func Button(label, customID string, opts ...ButtonOption) Component {
 		CustomID: customID,
 		Style:    discordgo.PrimaryButton,
 	}
-	for _, opt := range opts {
+	for _, opt := range defaultsFirst(opts) {
 		opt.applyToButton(button)
 	}
 	return button
@@ -353,12 +428,70 @@ func LinkButton(label, url string, opts ...ButtonOption) Component {
 		URL:   url,
 		Style: discordgo.LinkButton,
 	}
-	for _, opt := range opts {
+	for _, opt := range defaultsFirst(opts) {
 		opt.applyToButton(button)
 	}
 	return button
 }
 
+// IconButton creates an action button showing only an emoji. Unlike Button
+// with an empty label, it sends no label at all.
+func IconButton(emoji *discordgo.ComponentEmoji, customID string, opts ...ButtonOption) Component {
+	button := &discordgo.Button{
+		Emoji:    emoji,
+		CustomID: customID,
+		Style:    discordgo.PrimaryButton,
+	}
+	for _, opt := range defaultsFirst(opts) {
+		opt.applyToButton(button)
+	}
+	return labelessButton{button}
+}
+
+// PremiumButton creates a button that opens the purchase flow for a SKU. Its
+// label and icon come from the SKU, so it takes no label or custom ID.
+func PremiumButton(skuID string, opts ...ButtonOption) Component {
+	button := &discordgo.Button{
+		Style: discordgo.PremiumButton,
+		SKUID: skuID,
+	}
+	for _, opt := range defaultsFirst(opts) {
+		opt.applyToButton(button)
+	}
+	return labelessButton{button}
+}
+
+// labelessButton is a button sent without a label. discordgo always sends
+// the label, which Discord rejects on premium buttons and shows as empty
+// space on icon buttons, so the button is kept wrapped in the component tree.
+type labelessButton struct {
+	*discordgo.Button
+}
+
+func (b labelessButton) MarshalJSON() ([]byte, error) {
+	data, err := b.Button.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	delete(fields, "label")
+	return json.Marshal(fields)
+}
+
+// asButton returns the button behind c, seeing through labelessButton
+func asButton(c Component) (*discordgo.Button, bool) {
+	switch c := c.(type) {
+	case *discordgo.Button:
+		return c, true
+	case labelessButton:
+		return c.Button, true
+	}
+	return nil, false
+}
+
 type styleOption struct {
 	style ButtonStyle
 }
@@ -403,14 +536,29 @@ type FileOption interface {
 
 type fileComponent struct {
 	*discordgo.FileComponent
+	attachment *Attachment
+}
+
+func (f fileComponent) unwrap() Component {
+	return f.FileComponent
+}
+
+func (f fileComponent) attachments() []attachmentRef {
+	if f.attachment == nil {
+		return nil
+	}
+	return []attachmentRef{{f.attachment, &f.File.URL}}
 }
 
 func (f fileComponent) applyToContainer(c *discordgo.Container) {
-	c.Components = append(c.Components, f.FileComponent)
+	c.Components = append(c.Components, treeComponent(f))
 }
 
-// File creates a file component (for use in containers)
-func File(url string, opts ...FileOption) ContainerOption {
+// File creates a file component (can be used top-level or in containers)
+func File(url string, opts ...FileOption) interface {
+	Component
+	ContainerOption
+} {
 	file := &discordgo.FileComponent{
 		File: discordgo.UnfurledMediaItem{
 			URL: url,
@@ -419,48 +567,99 @@ func File(url string, opts ...FileOption) ContainerOption {
 	for _, opt := range opts {
 		opt.applyToFile(file)
 	}
-	return fileComponent{file}
+	return fileComponent{file, attachmentOf(opts)}
 }
 
-// MediaItem represents a media gallery item
+// MediaItem represents a media gallery item. When Attachment is set the item
+// shows the uploaded attachment instead of URL.
 type MediaItem struct {
 	URL         string
 	Description string
 	Spoiler     bool
+	Attachment  *Attachment
+}
+
+// MediaOption configures a MediaItem
+type MediaOption interface {
+	applyToMedia(*MediaItem)
 }
 
 // Media creates a media item for galleries
-func Media(url, description string, spoiler bool) MediaItem {
-	return MediaItem{
-		URL:         url,
-		Description: description,
-		Spoiler:     spoiler,
+func Media(url string, opts ...MediaOption) MediaItem {
+	item := MediaItem{URL: url}
+	for _, opt := range opts {
+		opt.applyToMedia(&item)
+	}
+	return item
+}
+
+type descriptionOption struct {
+	description string
+}
+
+func (o descriptionOption) applyToMedia(m *MediaItem) {
+	m.Description = o.description
+}
+
+// Description sets the media item's alt text
+func Description(text string) MediaOption {
+	return descriptionOption{text}
+}
+
+// optionalString returns nil for an empty string, so it is omitted from JSON
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
 	}
+	return &s
 }
 
 type mediaGalleryComponent struct {
 	*discordgo.MediaGallery
+	// attachment holds the attachment shown by each item, or nil
+	attachment []*Attachment
+}
+
+func (m mediaGalleryComponent) unwrap() Component {
+	return m.MediaGallery
+}
+
+func (m mediaGalleryComponent) attachments() []attachmentRef {
+	var refs []attachmentRef
+	for i, a := range m.attachment {
+		if a != nil {
+			refs = append(refs, attachmentRef{a, &m.Items[i].Media.URL})
+		}
+	}
+	return refs
 }
 
 func (m mediaGalleryComponent) applyToContainer(c *discordgo.Container) {
-	c.Components = append(c.Components, m.MediaGallery)
+	c.Components = append(c.Components, treeComponent(m))
 }
 
-// Gallery creates a media gallery component (for use in containers)
-func Gallery(items ...MediaItem) ContainerOption {
-	galleryItems := make([]discordgo.MediaGalleryItem, len(items))
+// Gallery creates a media gallery component (can be used top-level or in
+// containers). Empty descriptions are omitted.
+func Gallery(items ...MediaItem) interface {
+	Component
+	ContainerOption
+} {
+	gallery := &discordgo.MediaGallery{
+		Items: make([]discordgo.MediaGalleryItem, len(items)),
+	}
+	attachments := make([]*Attachment, len(items))
 	for i, item := range items {
-		galleryItems[i] = discordgo.MediaGalleryItem{
+		if item.Attachment != nil {
+			item.URL = item.Attachment.URL()
+			attachments[i] = item.Attachment
+		}
+		gallery.Items[i] = discordgo.MediaGalleryItem{
 			Media: discordgo.UnfurledMediaItem{
 				URL: item.URL,
 			},
-			Description: &item.Description,
+			Description: optionalString(item.Description),
 			Spoiler:     item.Spoiler,
 		}
 	}
-	return mediaGalleryComponent{
-		&discordgo.MediaGallery{
-			Items: galleryItems,
-		},
-	}
+	return mediaGalleryComponent{gallery, attachments}
 }
@@ -1,6 +1,8 @@
 package dmsg
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/bwmarrin/discordgo"
@@ -74,6 +76,20 @@ func TestUpdate(t *testing.T) {
 	})
 }
 
+func TestMessage(t *testing.T) {
+	t.Run("creates channel message", func(t *testing.T) {
+		message := Message(TextDisplay("Hello"))
+
+		if message.Flags != discordgo.MessageFlagsIsComponentsV2 {
+			t.Errorf("expected flags %d, got %d", discordgo.MessageFlagsIsComponentsV2, message.Flags)
+		}
+
+		if _, ok := message.Components[0].(*discordgo.TextDisplay); !ok {
+			t.Error("expected *discordgo.TextDisplay")
+		}
+	})
+}
+
 func TestContainer(t *testing.T) {
 	t.Run("creates empty container", func(t *testing.T) {
 		container := Container()
@@ -332,16 +348,12 @@ func TestThumbnail(t *testing.T) {
 		}
 	})
 
-	t.Run("handles empty description", func(t *testing.T) {
+	t.Run("omits empty description", func(t *testing.T) {
 		thumbnail := Thumbnail("http://example.com/image.png", "")
 
 		th := thumbnail.(*discordgo.Thumbnail)
-		if th.Description == nil {
-			t.Fatal("expected description to be set")
-		}
-
-		if *th.Description != "" {
-			t.Errorf("expected empty description, got '%s'", *th.Description)
+		if th.Description != nil {
+			t.Errorf("expected nil description, got '%s'", *th.Description)
 		}
 	})
 }
@@ -611,6 +623,95 @@ func TestButton(t *testing.T) {
 	})
 }
 
+func TestUnwrap(t *testing.T) {
+	t.Run("returns wrapped component", func(t *testing.T) {
+		if _, ok := Unwrap(TextDisplay("Hi")).(*discordgo.TextDisplay); !ok {
+			t.Error("expected *discordgo.TextDisplay")
+		}
+
+		b, ok := Unwrap(PremiumButton("1234")).(*discordgo.Button)
+		if !ok || b.SKUID != "1234" {
+			t.Errorf("expected premium button, got %v", b)
+		}
+	})
+
+	t.Run("returns discordgo components unchanged", func(t *testing.T) {
+		button := Button("Go", "go")
+		if Unwrap(button) != button {
+			t.Error("expected same component")
+		}
+	})
+}
+
+func TestIconButton(t *testing.T) {
+	t.Run("creates icon button", func(t *testing.T) {
+		b, ok := asButton(IconButton(&discordgo.ComponentEmoji{Name: "✖"}, "close", Style(Danger)))
+		if !ok {
+			t.Fatal("expected button")
+		}
+
+		if b.Emoji.Name != "✖" || b.CustomID != "close" || b.Style != discordgo.DangerButton {
+			t.Errorf("unexpected button %+v", b)
+		}
+	})
+
+	t.Run("omits label from JSON", func(t *testing.T) {
+		data, err := json.Marshal(IconButton(&discordgo.ComponentEmoji{Name: "✖"}, "close"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if strings.Contains(string(data), `"label"`) {
+			t.Errorf("expected no label, got %s", data)
+		}
+	})
+}
+
+func TestPremiumButton(t *testing.T) {
+	t.Run("creates premium button", func(t *testing.T) {
+		b, ok := asButton(PremiumButton("1234"))
+		if !ok {
+			t.Fatal("expected button")
+		}
+
+		if b.Style != discordgo.PremiumButton {
+			t.Errorf("expected style %d, got %d", discordgo.PremiumButton, b.Style)
+		}
+
+		if b.SKUID != "1234" {
+			t.Errorf("expected SKU ID 1234, got %s", b.SKUID)
+		}
+	})
+
+	t.Run("omits label from JSON", func(t *testing.T) {
+		data, err := json.Marshal(ActionRow(PremiumButton("1234")))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := `{"components":[{"disabled":false,"sku_id":"1234","style":6,"type":2}],"type":1}`
+		if string(data) != want {
+			t.Errorf("expected %s, got %s", want, data)
+		}
+	})
+
+	t.Run("applies options", func(t *testing.T) {
+		b, _ := asButton(PremiumButton("1234", Disabled()))
+
+		if !b.Disabled {
+			t.Error("expected disabled button")
+		}
+	})
+}
+
+func TestPremiumRequired(t *testing.T) {
+	response := PremiumRequired()
+
+	if response.Type != 10 {
+		t.Errorf("expected type 10, got %d", response.Type)
+	}
+}
+
 func TestLinkButton(t *testing.T) {
 	t.Run("creates link button", func(t *testing.T) {
 		label := "Visit Site"
@@ -654,6 +755,21 @@ func TestLinkButton(t *testing.T) {
 	})
 }
 
+func TestTopLevelMedia(t *testing.T) {
+	response := Response(
+		Gallery(Media("https://example.com/a.png")),
+		File("attachment://a.txt"),
+	)
+
+	if _, ok := response.Data.Components[0].(*discordgo.MediaGallery); !ok {
+		t.Error("expected *discordgo.MediaGallery")
+	}
+
+	if _, ok := response.Data.Components[1].(*discordgo.FileComponent); !ok {
+		t.Error("expected *discordgo.FileComponent")
+	}
+}
+
 func TestFile(t *testing.T) {
 	t.Run("creates file component", func(t *testing.T) {
 		url := "attachment://file.txt"
@@ -688,9 +804,8 @@ func TestMedia(t *testing.T) {
 	t.Run("creates media item", func(t *testing.T) {
 		url := "http://example.com/image.png"
 		desc := "test image"
-		spoiler := false
 
-		media := Media(url, desc, spoiler)
+		media := Media(url, Description(desc))
 
 		if media.URL != url {
 			t.Errorf("expected URL '%s', got '%s'", url, media.URL)
@@ -700,13 +815,13 @@ func TestMedia(t *testing.T) {
 			t.Errorf("expected description '%s', got '%s'", desc, media.Description)
 		}
 
-		if media.Spoiler != spoiler {
-			t.Errorf("expected spoiler %t, got %t", spoiler, media.Spoiler)
+		if media.Spoiler {
+			t.Error("expected spoiler to be false")
 		}
 	})
 
 	t.Run("creates spoiler media item", func(t *testing.T) {
-		media := Media("http://example.com/image.png", "desc", true)
+		media := Media("http://example.com/image.png", Description("desc"), Spoiler())
 
 		if !media.Spoiler {
 			t.Error("expected spoiler to be true")
@@ -715,9 +830,17 @@ func TestMedia(t *testing.T) {
 }
 
 func TestGallery(t *testing.T) {
+	t.Run("omits empty description", func(t *testing.T) {
+		gallery := Gallery(Media("http://example.com/image.png")).(mediaGalleryComponent)
+
+		if gallery.Items[0].Description != nil {
+			t.Errorf("expected nil description, got '%s'", *gallery.Items[0].Description)
+		}
+	})
+
 	t.Run("creates gallery", func(t *testing.T) {
-		item1 := Media("http://example.com/image1.png", "Image 1", false)
-		item2 := Media("http://example.com/image2.png", "Image 2", true)
+		item1 := Media("http://example.com/image1.png", Description("Image 1"))
+		item2 := Media("http://example.com/image2.png", Description("Image 2"), Spoiler())
 
 		gallery := Gallery(item1, item2)
 
@@ -0,0 +1,219 @@
+package dmsg
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/thomasgtaylor/dmsg/internal/markdown"
+)
+
+// PlainText renders a response as readable plain text, for logs, push
+// notifications and audit channels. Markdown is stripped or simplified,
+// buttons are listed as "[Label]", link buttons as "Label: URL" and media by
+// their descriptions.
+func PlainText(response *discordgo.InteractionResponse) string {
+	if response == nil || response.Data == nil {
+		return ""
+	}
+	var lines []string
+	if response.Data.Content != "" {
+		lines = append(lines, StripMarkdown(response.Data.Content))
+	}
+	lines = appendPlainText(lines, unwrapComponents(response.Data.Components))
+	return joinPlainText(lines)
+}
+
+func appendPlainText(lines []string, components []Component) []string {
+	for _, c := range components {
+		switch c := Unwrap(c).(type) {
+		case *discordgo.Container:
+			lines = appendPlainText(lines, c.Components)
+		case *discordgo.Section:
+			lines = appendPlainText(lines, c.Components)
+			if c.Accessory != nil {
+				lines = appendPlainText(lines, []Component{c.Accessory})
+			}
+		case *discordgo.TextDisplay:
+			lines = append(lines, StripMarkdown(c.Content))
+		case *discordgo.Separator:
+			lines = append(lines, "")
+		case *discordgo.ActionsRow:
+			labels := make([]string, 0, len(c.Components))
+			for _, child := range c.Components {
+				if label := plainTextControl(child); label != "" {
+					labels = append(labels, label)
+				}
+			}
+			lines = append(lines, strings.Join(labels, " "))
+		case *discordgo.Thumbnail:
+			lines = append(lines, plainTextMedia("image", c.Description))
+		case *discordgo.MediaGallery:
+			for _, item := range c.Items {
+				lines = append(lines, plainTextMedia("image", item.Description))
+			}
+		case *discordgo.FileComponent:
+			name := c.File.URL[strings.LastIndex(c.File.URL, "/")+1:]
+			lines = append(lines, "(file: "+name+")")
+		default:
+			if label := plainTextControl(c); label != "" {
+				lines = append(lines, label)
+			}
+		}
+	}
+	return lines
+}
+
+func plainTextControl(c Component) string {
+	if b, ok := asButton(c); ok {
+		c = b
+	}
+	switch c := c.(type) {
+	case *discordgo.Button:
+		if c.Style == discordgo.PremiumButton {
+			return "[Premium]"
+		}
+		if c.Style == discordgo.LinkButton {
+			if c.Label == "" {
+				return c.URL
+			}
+			return c.Label + ": " + c.URL
+		}
+		label := c.Label
+		if label == "" && c.Emoji != nil {
+			label = c.Emoji.Name
+		}
+		return "[" + label + "]"
+	case *discordgo.SelectMenu:
+		return "[" + c.Placeholder + "]"
+	}
+	return ""
+}
+
+func plainTextMedia(kind string, description *string) string {
+	if description == nil || *description == "" {
+		return "(" + kind + ")"
+	}
+	return "(" + kind + ": " + *description + ")"
+}
+
+// joinPlainText joins rendered lines, collapsing runs of blank lines and
+// trimming blank lines from either end
+func joinPlainText(lines []string) string {
+	var out []string
+	blank := true
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			if !blank {
+				out = append(out, "")
+			}
+			blank = true
+			continue
+		}
+		out = append(out, line)
+		blank = false
+	}
+	if len(out) > 0 && out[len(out)-1] == "" {
+		out = out[:len(out)-1]
+	}
+	return strings.Join(out, "\n")
+}
+
+var (
+	blockPrefixRule    = regexp.MustCompile(`^(?:#{1,3} |-# |>>> |> ?)`)
+	bulletRule         = regexp.MustCompile(`^(\s*)\* `)
+	maskedLinkRule     = regexp.MustCompile(`\[([^\]]+)\]\(<?([^)\s>]+)>?\)`)
+	customEmojiRule    = regexp.MustCompile(`<a?:(\w+):\d+>`)
+	userMentionRule    = regexp.MustCompile(`<@!?(\d+)>`)
+	roleMentionRule    = regexp.MustCompile(`<@&(\d+)>`)
+	channelMentionRule = regexp.MustCompile(`<#(\d+)>`)
+	timestampRule      = regexp.MustCompile(`<t:(-?\d+)(?::[tTdDfFR])?>`)
+	escapeRule         = regexp.MustCompile(`\\([\\*_~|` + "`" + `>#\-\[\]()])`)
+)
+
+// emphasisRules match paired emphasis markers, longest first. Asterisks and
+// underscores must hug their text, so "2 * 3" and snake_case are left alone.
+var emphasisRules = []*regexp.Regexp{
+	regexp.MustCompile(`\*\*(\S(?:.*?\S)?)\*\*`),
+	regexp.MustCompile(`__(\S(?:.*?\S)?)__`),
+	regexp.MustCompile(`~~(.+?)~~`),
+	regexp.MustCompile(`\|\|(.+?)\|\|`),
+	regexp.MustCompile(`\*(\S(?:.*?\S)?)\*`),
+	regexp.MustCompile(`\b_(\S(?:.*?\S)?)_\b`),
+}
+
+// StripMarkdown removes Discord markdown formatting from text, keeping its
+// content readable: headings and quotes lose their markers, masked links
+// become "text (url)", mentions and custom emoji are simplified and code
+// blocks keep their contents verbatim
+func StripMarkdown(text string) string {
+	lines := strings.Split(text, "\n")
+	out := make([]string, 0, len(lines))
+	inCode := false
+	for _, line := range lines {
+		if markdown.IsFence(line) {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			out = append(out, line)
+			continue
+		}
+		line = blockPrefixRule.ReplaceAllString(line, "")
+		line = bulletRule.ReplaceAllString(line, "$1- ")
+		out = append(out, stripInline(line))
+	}
+	return strings.Join(out, "\n")
+}
+
+func stripInline(line string) string {
+	parts := strings.Split(line, "`")
+	for i := range parts {
+		// Odd parts are inline code spans, which are kept as written.
+		if i%2 == 1 && i < len(parts)-1 {
+			continue
+		}
+		parts[i] = stripFormatting(parts[i])
+	}
+	return strings.Join(parts, "")
+}
+
+func stripFormatting(s string) string {
+	s = maskedLinkRule.ReplaceAllStringFunc(s, func(m string) string {
+		sub := maskedLinkRule.FindStringSubmatch(m)
+		if sub[1] == sub[2] {
+			return sub[2]
+		}
+		return sub[1] + " (" + sub[2] + ")"
+	})
+	s = customEmojiRule.ReplaceAllString(s, ":$1:")
+	s = userMentionRule.ReplaceAllString(s, "@$1")
+	s = roleMentionRule.ReplaceAllString(s, "@&$1")
+	s = channelMentionRule.ReplaceAllString(s, "#$1")
+	s = timestampRule.ReplaceAllStringFunc(s, func(m string) string {
+		seconds, err := strconv.ParseInt(timestampRule.FindStringSubmatch(m)[1], 10, 64)
+		if err != nil {
+			return m
+		}
+		return time.Unix(seconds, 0).UTC().Format("2006-01-02 15:04 UTC")
+	})
+
+	// Move escaped characters into the private use area so the emphasis
+	// rule leaves them alone, then restore them.
+	s = escapeRule.ReplaceAllStringFunc(s, func(m string) string {
+		return string(escapedBase + rune(m[1]))
+	})
+	for _, rule := range emphasisRules {
+		s = rule.ReplaceAllString(s, "$1")
+	}
+	return strings.Map(func(r rune) rune {
+		if r >= escapedBase && r < escapedBase+0x80 {
+			return r - escapedBase
+		}
+		return r
+	}, s)
+}
+
+const escapedBase = 0xE000
@@ -0,0 +1,122 @@
+package dmsg
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestPlainText(t *testing.T) {
+	t.Run("flattens container tree", func(t *testing.T) {
+		response := Response(
+			Container(
+				AccentColor(5763719),
+				Section(
+					TextDisplay("## Success!"),
+					TextDisplay("You won **1,000 coins**"),
+					Accessory(Thumbnail("https://example.com/trophy.png", "Trophy")),
+				),
+				Separator(),
+				ActionRow(
+					Button("Play Again", "play_again", Style(Success)),
+					Button("Quit", "quit"),
+					LinkButton("Rules", "https://example.com/rules"),
+				),
+			),
+		)
+
+		expected := "Success!\nYou won 1,000 coins\n(image: Trophy)\n\n[Play Again] [Quit] Rules: https://example.com/rules"
+		if got := PlainText(response); got != expected {
+			t.Errorf("expected %q, got %q", expected, got)
+		}
+	})
+
+	t.Run("describes media", func(t *testing.T) {
+		response := Response(
+			Container(
+				Gallery(
+					Media("https://example.com/1.png", Description("First")),
+					Media("https://example.com/2.png"),
+				),
+				File("attachment://report.csv"),
+			),
+		)
+
+		expected := "(image: First)\n(image)\n(file: report.csv)"
+		if got := PlainText(response); got != expected {
+			t.Errorf("expected %q, got %q", expected, got)
+		}
+	})
+
+	t.Run("uses emoji for icon buttons", func(t *testing.T) {
+		response := Response(
+			ActionRow(Button("", "next", Emoji(&discordgo.ComponentEmoji{Name: "▶"}))),
+		)
+
+		if got := PlainText(response); got != "[▶]" {
+			t.Errorf("expected %q, got %q", "[▶]", got)
+		}
+	})
+
+	t.Run("names premium buttons", func(t *testing.T) {
+		response := Response(ActionRow(PremiumButton("123")))
+
+		if got := PlainText(response); got != "[Premium]" {
+			t.Errorf("expected %q, got %q", "[Premium]", got)
+		}
+	})
+
+	t.Run("collapses repeated separators", func(t *testing.T) {
+		response := Response(
+			Separator(),
+			TextDisplay("a"),
+			Separator(),
+			Separator(),
+			TextDisplay("b"),
+			Separator(),
+		)
+
+		if got := PlainText(response); got != "a\n\nb" {
+			t.Errorf("expected %q, got %q", "a\n\nb", got)
+		}
+	})
+
+	t.Run("handles nil response", func(t *testing.T) {
+		if got := PlainText(nil); got != "" {
+			t.Errorf("expected empty string, got %q", got)
+		}
+	})
+}
+
+func TestStripMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"headings", "# One\n## Two\n-# small", "One\nTwo\nsmall"},
+		{"emphasis", "**bold** *it* __under__ ~~strike~~ ||spoiler||", "bold it under strike spoiler"},
+		{"unpaired asterisks kept", "2 * 3 = 6 and a*b", "2 * 3 = 6 and a*b"},
+		{"nested emphasis", "**bold _italic_**", "bold italic"},
+		{"underscore italics keep snake case", "_it_ snake_case", "it snake_case"},
+		{"quotes", "> quoted\n>>> block", "quoted\nblock"},
+		{"bullets", "* one\n- two", "- one\n- two"},
+		{"masked links", "[docs](https://example.com)", "docs (https://example.com)"},
+		{"bare masked links", "[https://example.com](https://example.com)", "https://example.com"},
+		{"inline code kept", "run `**x**` now", "run **x** now"},
+		{"code blocks kept", "```go\nx := *y\n```", "x := *y"},
+		{"one-line code blocks", "before\n```inline code```\nafter **bold**", "before\ninline code\nafter bold"},
+		{"escapes", `\*not bold\*`, "*not bold*"},
+		{"mentions", "<@123> <@!4> <@&5> <#6>", "@123 @4 @&5 #6"},
+		{"custom emoji", "<a:party:123>", ":party:"},
+		{"timestamps", "<t:0:R>", "1970-01-01 00:00 UTC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StripMarkdown(tt.in); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package preview
+
+import (
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/thomasgtaylor/dmsg/internal/markdown"
+)
+
+// markdownToHTML converts the subset of Discord markdown supported in text
+// displays into HTML. It is intentionally forgiving: anything it does not
+// recognise is rendered as escaped text.
+func markdownToHTML(src string) string {
+	var b strings.Builder
+	var paragraph, list []string
+	var code []string
+	inCode := false
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			b.WriteString("<p>" + renderInline(strings.Join(paragraph, "\n")) + "</p>")
+			paragraph = nil
+		}
+	}
+	flushList := func() {
+		if len(list) > 0 {
+			b.WriteString("<ul>")
+			for _, item := range list {
+				b.WriteString("<li>" + renderInline(item) + "</li>")
+			}
+			b.WriteString("</ul>")
+			list = nil
+		}
+	}
+
+	for _, line := range strings.Split(src, "\n") {
+		if inCode {
+			if markdown.IsFence(line) {
+				b.WriteString("<pre><code>" + html.EscapeString(strings.Join(code, "\n")) + "</code></pre>")
+				code = nil
+				inCode = false
+			} else {
+				code = append(code, line)
+			}
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if markdown.IsFence(line) {
+			flushParagraph()
+			flushList()
+			inCode = true
+			continue
+		}
+		if item, ok := listItem(trimmed); ok {
+			flushParagraph()
+			list = append(list, item)
+			continue
+		}
+		flushList()
+
+		switch {
+		case strings.HasPrefix(trimmed, "### "):
+			flushParagraph()
+			b.WriteString("<h3>" + renderInline(trimmed[4:]) + "</h3>")
+		case strings.HasPrefix(trimmed, "## "):
+			flushParagraph()
+			b.WriteString("<h2>" + renderInline(trimmed[3:]) + "</h2>")
+		case strings.HasPrefix(trimmed, "# "):
+			flushParagraph()
+			b.WriteString("<h1>" + renderInline(trimmed[2:]) + "</h1>")
+		case strings.HasPrefix(trimmed, "-# "):
+			flushParagraph()
+			b.WriteString("<small>" + renderInline(trimmed[3:]) + "</small>")
+		case strings.HasPrefix(trimmed, "> ") || trimmed == ">":
+			flushParagraph()
+			b.WriteString("<blockquote>" + renderInline(strings.TrimPrefix(trimmed[1:], " ")) + "</blockquote>")
+		default:
+			paragraph = append(paragraph, line)
+		}
+	}
+
+	if inCode {
+		b.WriteString("<pre><code>" + html.EscapeString(strings.Join(code, "\n")) + "</code></pre>")
+	}
+	flushParagraph()
+	flushList()
+	return b.String()
+}
+
+func listItem(line string) (string, bool) {
+	for _, marker := range []string{"- ", "* "} {
+		if strings.HasPrefix(line, marker) {
+			return line[len(marker):], true
+		}
+	}
+	return "", false
+}
+
+var inlineRules = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`\[([^\]]+)\]\((https?://[^)\s]+)\)`), `<a href="$2">$1</a>`},
+	{regexp.MustCompile(`\*\*(.+?)\*\*`), `<strong>$1</strong>`},
+	{regexp.MustCompile(`__(.+?)__`), `<u>$1</u>`},
+	{regexp.MustCompile(`\*([^*\s][^*]*?)\*`), `<em>$1</em>`},
+	{regexp.MustCompile(`\b_([^_]+?)_\b`), `<em>$1</em>`},
+	{regexp.MustCompile(`~~(.+?)~~`), `<s>$1</s>`},
+	{regexp.MustCompile(`\|\|(.+?)\|\|`), `<span class="dmsg-spoiler-text">$1</span>`},
+}
+
+// renderInline escapes text and applies inline formatting, leaving the
+// contents of code spans untouched
+func renderInline(text string) string {
+	parts := strings.Split(text, "`")
+	var b strings.Builder
+	for i, part := range parts {
+		escaped := html.EscapeString(part)
+		switch {
+		case i%2 == 1 && i < len(parts)-1:
+			// Empty spans are the extra backticks of a "```code```" span.
+			if part != "" {
+				b.WriteString("<code>" + escaped + "</code>")
+			}
+		case i%2 == 1:
+			// Unterminated code span: keep the backtick as typed.
+			b.WriteString("`" + applyInlineRules(escaped))
+		default:
+			b.WriteString(applyInlineRules(escaped))
+		}
+	}
+	return b.String()
+}
+
+func applyInlineRules(text string) string {
+	for _, rule := range inlineRules {
+		text = rule.pattern.ReplaceAllString(text, rule.replacement)
+	}
+	return text
+}
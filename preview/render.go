@@ -0,0 +1,272 @@
+// Package preview renders dmsg component trees as HTML that mimics Discord's
+// dark theme, so messages can be reviewed in a browser without a Discord
+// client.
+//
+// Render writes a single message as an HTML fragment, and Server serves a set
+// of named previews with live reload:
+//
+//	srv := preview.NewServer()
+//	srv.Register("welcome", func() *discordgo.InteractionResponse {
+//	    return dmsg.Response(dmsg.TextDisplay("## Welcome!"))
+//	})
+//	http.ListenAndServe("localhost:8080", srv)
+package preview
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/thomasgtaylor/dmsg"
+)
+
+// Render writes the components as an HTML fragment styled by Stylesheet
+func Render(w io.Writer, components []discordgo.MessageComponent) error {
+	var b strings.Builder
+	b.WriteString(`<div class="dmsg-message">`)
+	for _, c := range components {
+		renderComponent(&b, c)
+	}
+	b.WriteString(`</div>`)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// RenderResponse writes the response's components as an HTML fragment,
+// marking ephemeral responses the way Discord does
+func RenderResponse(w io.Writer, response *discordgo.InteractionResponse) error {
+	if response == nil || response.Data == nil {
+		return Render(w, nil)
+	}
+	if response.Data.Flags&discordgo.MessageFlagsEphemeral != 0 {
+		if _, err := io.WriteString(w, `<div class="dmsg-ephemeral">Only you can see this</div>`); err != nil {
+			return err
+		}
+	}
+	return Render(w, response.Data.Components)
+}
+
+func renderComponent(b *strings.Builder, c discordgo.MessageComponent) {
+	switch c := dmsg.Unwrap(c).(type) {
+	case *discordgo.Container:
+		renderContainer(b, c)
+	case *discordgo.Section:
+		renderSection(b, c)
+	case *discordgo.TextDisplay:
+		fmt.Fprintf(b, `<div class="dmsg-text">%s</div>`, markdownToHTML(c.Content))
+	case *discordgo.Thumbnail:
+		renderMedia(b, "dmsg-thumbnail", c.Media.URL, c.Description, c.Spoiler)
+	case *discordgo.MediaGallery:
+		fmt.Fprintf(b, `<div class="dmsg-gallery dmsg-gallery-%d">`, min(len(c.Items), 4))
+		for _, item := range c.Items {
+			renderMedia(b, "dmsg-gallery-item", item.Media.URL, item.Description, item.Spoiler)
+		}
+		b.WriteString(`</div>`)
+	case *discordgo.FileComponent:
+		renderFile(b, c)
+	case *discordgo.Separator:
+		renderSeparator(b, c)
+	case *discordgo.ActionsRow:
+		b.WriteString(`<div class="dmsg-row">`)
+		for _, child := range c.Components {
+			renderComponent(b, child)
+		}
+		b.WriteString(`</div>`)
+	case *discordgo.Button:
+		renderButton(b, c)
+	case *discordgo.SelectMenu:
+		fmt.Fprintf(b, `<div class="dmsg-select">%s<span class="dmsg-chevron">⌄</span></div>`, html.EscapeString(c.Placeholder))
+	default:
+		if c == nil {
+			return
+		}
+		fmt.Fprintf(b, `<div class="dmsg-unknown">unsupported component type %d</div>`, c.Type())
+	}
+}
+
+func renderContainer(b *strings.Builder, c *discordgo.Container) {
+	style := ""
+	if c.AccentColor != nil {
+		style = fmt.Sprintf(` style="border-left-color: #%06x"`, *c.AccentColor)
+	}
+	class := "dmsg-container"
+	if c.AccentColor != nil {
+		class += " dmsg-accent"
+	}
+	if c.Spoiler {
+		class += " dmsg-spoiler"
+	}
+	fmt.Fprintf(b, `<div class="%s"%s>`, class, style)
+	for _, child := range c.Components {
+		renderComponent(b, child)
+	}
+	b.WriteString(`</div>`)
+}
+
+func renderSection(b *strings.Builder, s *discordgo.Section) {
+	b.WriteString(`<div class="dmsg-section"><div class="dmsg-section-body">`)
+	for _, child := range s.Components {
+		renderComponent(b, child)
+	}
+	b.WriteString(`</div>`)
+	if s.Accessory != nil {
+		b.WriteString(`<div class="dmsg-accessory">`)
+		renderComponent(b, s.Accessory)
+		b.WriteString(`</div>`)
+	}
+	b.WriteString(`</div>`)
+}
+
+func renderMedia(b *strings.Builder, class, url string, description *string, spoiler bool) {
+	alt := ""
+	if description != nil {
+		alt = *description
+	}
+	if spoiler {
+		class += " dmsg-spoiler"
+	}
+	fmt.Fprintf(b, `<div class="%s"><img src="%s" alt="%s" title="%s"></div>`,
+		class, html.EscapeString(mediaSource(url)), html.EscapeString(alt), html.EscapeString(alt))
+}
+
+// mediaSource replaces attachment:// URLs, which only resolve inside
+// Discord, with an inline placeholder image
+func mediaSource(url string) string {
+	if strings.HasPrefix(url, "attachment://") {
+		return "data:image/svg+xml," + strings.ReplaceAll(placeholderSVG, "#", "%23")
+	}
+	return url
+}
+
+const placeholderSVG = `<svg xmlns="http://www.w3.org/2000/svg" width="320" height="180"><rect width="100%" height="100%" fill="#1e1f22"/><text x="50%" y="50%" fill="#949ba4" font-family="sans-serif" font-size="14" text-anchor="middle">attachment</text></svg>`
+
+func renderFile(b *strings.Builder, f *discordgo.FileComponent) {
+	name := f.File.URL
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	class := "dmsg-file"
+	if f.Spoiler {
+		class += " dmsg-spoiler"
+	}
+	fmt.Fprintf(b, `<div class="%s"><span class="dmsg-file-icon">📄</span><span class="dmsg-file-name">%s</span></div>`,
+		class, html.EscapeString(name))
+}
+
+func renderSeparator(b *strings.Builder, s *discordgo.Separator) {
+	class := "dmsg-separator"
+	if s.Spacing != nil && *s.Spacing == discordgo.SeparatorSpacingSizeLarge {
+		class += " dmsg-separator-large"
+	}
+	if s.Divider == nil || *s.Divider {
+		class += " dmsg-divider"
+	}
+	fmt.Fprintf(b, `<div class="%s"></div>`, class)
+}
+
+func renderButton(b *strings.Builder, btn *discordgo.Button) {
+	class := "dmsg-button dmsg-button-" + buttonStyleName(btn.Style)
+	if btn.Disabled {
+		class += " dmsg-disabled"
+	}
+	title := btn.CustomID
+	switch btn.Style {
+	case discordgo.LinkButton:
+		title = btn.URL
+	case discordgo.PremiumButton:
+		title = "SKU " + btn.SKUID
+	}
+	fmt.Fprintf(b, `<span class="%s" title="%s">`, class, html.EscapeString(title))
+	if btn.Emoji != nil {
+		renderEmoji(b, btn.Emoji)
+	}
+	if btn.Label != "" {
+		fmt.Fprintf(b, `<span class="dmsg-label">%s</span>`, html.EscapeString(btn.Label))
+	} else if btn.Style == discordgo.PremiumButton {
+		b.WriteString(`<span class="dmsg-emoji">✦</span><span class="dmsg-label">Premium</span>`)
+	}
+	if btn.Style == discordgo.LinkButton {
+		b.WriteString(`<span class="dmsg-external">↗</span>`)
+	}
+	b.WriteString(`</span>`)
+}
+
+func renderEmoji(b *strings.Builder, emoji *discordgo.ComponentEmoji) {
+	if emoji.ID == "" {
+		fmt.Fprintf(b, `<span class="dmsg-emoji">%s</span>`, html.EscapeString(emoji.Name))
+		return
+	}
+	ext := "png"
+	if emoji.Animated {
+		ext = "gif"
+	}
+	fmt.Fprintf(b, `<img class="dmsg-emoji" src="https://cdn.discordapp.com/emojis/%s.%s" alt=":%s:">`,
+		html.EscapeString(emoji.ID), ext, html.EscapeString(emoji.Name))
+}
+
+func buttonStyleName(style discordgo.ButtonStyle) string {
+	switch style {
+	case discordgo.SecondaryButton, discordgo.LinkButton:
+		return "secondary"
+	case discordgo.SuccessButton:
+		return "success"
+	case discordgo.DangerButton:
+		return "danger"
+	case discordgo.PremiumButton:
+		return "premium"
+	default:
+		return "primary"
+	}
+}
+
+// Stylesheet is the CSS used by rendered previews, modelled on Discord's
+// dark theme
+const Stylesheet = `
+body { background: #313338; color: #dbdee1; font-family: "gg sans", "Noto Sans", "Helvetica Neue", Helvetica, Arial, sans-serif; font-size: 16px; line-height: 1.375; margin: 0; padding: 24px; }
+a { color: #00a8fc; text-decoration: none; }
+a:hover { text-decoration: underline; }
+.dmsg-message { display: flex; flex-direction: column; gap: 8px; max-width: 600px; }
+.dmsg-ephemeral { color: #949ba4; font-size: 12px; margin-bottom: 4px; }
+.dmsg-container { background: #2b2d31; border: 1px solid #1e1f22; border-radius: 8px; padding: 16px; display: flex; flex-direction: column; gap: 8px; }
+.dmsg-container.dmsg-accent { border-left: 4px solid; }
+.dmsg-section { display: flex; gap: 12px; align-items: flex-start; }
+.dmsg-section-body { flex: 1; display: flex; flex-direction: column; gap: 8px; }
+.dmsg-accessory { flex: none; }
+.dmsg-text h1, .dmsg-text h2, .dmsg-text h3 { margin: 0; color: #f2f3f5; line-height: 1.25; }
+.dmsg-text h1 { font-size: 24px; } .dmsg-text h2 { font-size: 20px; } .dmsg-text h3 { font-size: 16px; }
+.dmsg-text p { margin: 0; white-space: pre-wrap; }
+.dmsg-text small { display: block; color: #949ba4; font-size: 12px; }
+.dmsg-text ul { margin: 0; padding-left: 20px; }
+.dmsg-text blockquote { margin: 0; padding-left: 12px; border-left: 4px solid #4e5058; }
+.dmsg-text code { background: #1e1f22; border-radius: 4px; padding: 0 3px; font-family: Consolas, "Andale Mono WT", Monaco, monospace; font-size: 85%; }
+.dmsg-text pre { margin: 0; background: #1e1f22; border: 1px solid #1e1f22; border-radius: 4px; padding: 8px; overflow-x: auto; }
+.dmsg-text pre code { background: none; padding: 0; white-space: pre; }
+.dmsg-text .dmsg-spoiler-text { background: #1e1f22; color: transparent; border-radius: 3px; cursor: pointer; }
+.dmsg-text .dmsg-spoiler-text:hover { color: inherit; }
+.dmsg-thumbnail img { width: 85px; height: 85px; object-fit: cover; border-radius: 8px; }
+.dmsg-gallery { display: grid; gap: 4px; border-radius: 8px; overflow: hidden; }
+.dmsg-gallery-1 { grid-template-columns: 1fr; } .dmsg-gallery-2 { grid-template-columns: 1fr 1fr; }
+.dmsg-gallery-3, .dmsg-gallery-4 { grid-template-columns: 1fr 1fr; }
+.dmsg-gallery-item img { width: 100%; height: 100%; max-height: 300px; object-fit: cover; display: block; }
+.dmsg-spoiler img { filter: blur(44px); }
+.dmsg-spoiler:hover img { filter: none; }
+.dmsg-container.dmsg-spoiler > * { filter: blur(6px); }
+.dmsg-container.dmsg-spoiler:hover > * { filter: none; }
+.dmsg-file { display: flex; align-items: center; gap: 8px; background: #2b2d31; border: 1px solid #1e1f22; border-radius: 8px; padding: 12px; }
+.dmsg-file-name { color: #00a8fc; }
+.dmsg-separator { height: 8px; position: relative; }
+.dmsg-separator-large { height: 16px; }
+.dmsg-separator.dmsg-divider::after { content: ""; position: absolute; left: 0; right: 0; top: 50%; border-top: 1px solid #3f4147; }
+.dmsg-row { display: flex; flex-wrap: wrap; gap: 8px; }
+.dmsg-button { display: inline-flex; align-items: center; gap: 6px; min-height: 32px; padding: 2px 16px; border-radius: 8px; color: #fff; font-size: 14px; font-weight: 500; cursor: pointer; box-sizing: border-box; }
+.dmsg-button-primary { background: #5865f2; } .dmsg-button-secondary { background: #4e5058; }
+.dmsg-button-success { background: #248046; } .dmsg-button-danger { background: #da373c; }
+.dmsg-button-premium { background: linear-gradient(90deg, #8547c6, #b845c1); }
+.dmsg-disabled { opacity: 0.5; cursor: not-allowed; }
+.dmsg-emoji { height: 1.1em; }
+img.dmsg-emoji { width: 1.1em; vertical-align: -0.2em; }
+.dmsg-select { display: flex; justify-content: space-between; background: #1e1f22; border-radius: 4px; padding: 8px 12px; color: #949ba4; }
+.dmsg-unknown { color: #f23f43; font-size: 12px; }
+`
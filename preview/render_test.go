@@ -0,0 +1,148 @@
+package preview
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/thomasgtaylor/dmsg"
+)
+
+func render(t *testing.T, response *discordgo.InteractionResponse) string {
+	t.Helper()
+	var b strings.Builder
+	if err := RenderResponse(&b, response); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return b.String()
+}
+
+func TestRender(t *testing.T) {
+	t.Run("renders container with accent color", func(t *testing.T) {
+		out := render(t, dmsg.Response(
+			dmsg.Container(
+				dmsg.AccentColor(0x57F287),
+				dmsg.TextDisplay("hello"),
+			),
+		))
+
+		if !strings.Contains(out, `class="dmsg-container dmsg-accent"`) {
+			t.Errorf("expected accented container, got %s", out)
+		}
+
+		if !strings.Contains(out, "border-left-color: #57f287") {
+			t.Errorf("expected accent color, got %s", out)
+		}
+	})
+
+	t.Run("renders section with thumbnail accessory", func(t *testing.T) {
+		out := render(t, dmsg.Response(
+			dmsg.Section(
+				dmsg.TextDisplay("text"),
+				dmsg.Accessory(dmsg.Thumbnail("https://example.com/a.png", "An <image>")),
+			),
+		))
+
+		if !strings.Contains(out, `<div class="dmsg-accessory"><div class="dmsg-thumbnail"><img src="https://example.com/a.png" alt="An &lt;image&gt;"`) {
+			t.Errorf("expected thumbnail accessory, got %s", out)
+		}
+	})
+
+	t.Run("renders buttons by style", func(t *testing.T) {
+		out := render(t, dmsg.Response(
+			dmsg.ActionRow(
+				dmsg.Button("Go", "go", dmsg.Style(dmsg.Success)),
+				dmsg.Button("Stop", "stop", dmsg.Style(dmsg.Danger), dmsg.Disabled()),
+				dmsg.LinkButton("Docs", "https://example.com"),
+			),
+		))
+
+		for _, want := range []string{
+			`dmsg-button dmsg-button-success`,
+			`dmsg-button dmsg-button-danger dmsg-disabled`,
+			`title="https://example.com"`,
+			`dmsg-external`,
+		} {
+			if !strings.Contains(out, want) {
+				t.Errorf("expected %q in %s", want, out)
+			}
+		}
+	})
+
+	t.Run("renders premium buttons", func(t *testing.T) {
+		out := render(t, dmsg.Response(dmsg.ActionRow(dmsg.PremiumButton("123"))))
+
+		for _, want := range []string{`dmsg-button dmsg-button-premium`, `title="SKU 123"`, `Premium`} {
+			if !strings.Contains(out, want) {
+				t.Errorf("expected %q in %s", want, out)
+			}
+		}
+	})
+
+	t.Run("renders custom emoji from cdn", func(t *testing.T) {
+		out := render(t, dmsg.Response(
+			dmsg.ActionRow(
+				dmsg.Button("Party", "party", dmsg.Emoji(&discordgo.ComponentEmoji{Name: "party", ID: "123", Animated: true})),
+			),
+		))
+
+		if !strings.Contains(out, `src="https://cdn.discordapp.com/emojis/123.gif"`) {
+			t.Errorf("expected animated emoji image, got %s", out)
+		}
+	})
+
+	t.Run("marks ephemeral responses", func(t *testing.T) {
+		out := render(t, dmsg.Ephemeral(dmsg.TextDisplay("secret")))
+
+		if !strings.Contains(out, "Only you can see this") {
+			t.Errorf("expected ephemeral notice, got %s", out)
+		}
+	})
+
+	t.Run("replaces attachment urls with placeholder", func(t *testing.T) {
+		out := render(t, dmsg.Response(
+			dmsg.Container(dmsg.Gallery(dmsg.Media("attachment://chart.png", dmsg.Description("chart")))),
+		))
+
+		if strings.Contains(out, "attachment://") {
+			t.Errorf("expected attachment url to be replaced, got %s", out)
+		}
+	})
+
+	t.Run("handles nil response", func(t *testing.T) {
+		out := render(t, nil)
+
+		if out != `<div class="dmsg-message"></div>` {
+			t.Errorf("unexpected output %s", out)
+		}
+	})
+}
+
+func TestMarkdownToHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"heading", "## Title", "<h2>Title</h2>"},
+		{"subtext", "-# small", "<small>small</small>"},
+		{"bold and italic", "**a** *b*", "<p><strong>a</strong> <em>b</em></p>"},
+		{"underline and strike", "__u__ ~~s~~", "<p><u>u</u> <s>s</s></p>"},
+		{"spoiler", "||x||", `<p><span class="dmsg-spoiler-text">x</span></p>`},
+		{"link", "[site](https://example.com)", `<p><a href="https://example.com">site</a></p>`},
+		{"inline code is literal", "`**x**`", "<p><code>**x**</code></p>"},
+		{"code block", "```go\na < b\n```", "<pre><code>a &lt; b</code></pre>"},
+		{"one-line code block", "```x```\n# A", "<p><code>x</code></p><h1>A</h1>"},
+		{"list", "- one\n- two", "<ul><li>one</li><li>two</li></ul>"},
+		{"quote", "> quoted", "<blockquote>quoted</blockquote>"},
+		{"escapes html", "<b>", "<p>&lt;b&gt;</p>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markdownToHTML(tt.in); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
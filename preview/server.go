@@ -0,0 +1,178 @@
+package preview
+
+import (
+	"html/template"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Builder produces the response shown for a named preview. It is called on
+// every page load so previews always reflect the current code.
+type Builder func() *discordgo.InteractionResponse
+
+// Server is an http.Handler serving registered previews. The index page
+// lists every preview, each preview has its own page, and open pages reload
+// themselves when the set of previews changes or the server restarts.
+type Server struct {
+	mux *http.ServeMux
+
+	mu       sync.RWMutex
+	builders map[string]Builder
+	started  int64
+	revision int
+}
+
+// NewServer creates an empty preview server
+func NewServer() *Server {
+	s := &Server{
+		mux:      http.NewServeMux(),
+		builders: map[string]Builder{},
+		started:  time.Now().UnixNano(),
+	}
+	s.mux.HandleFunc("GET /{$}", s.handleIndex)
+	s.mux.HandleFunc("GET /preview/{name}", s.handlePreview)
+	s.mux.HandleFunc("GET /_live", s.handleLive)
+	return s
+}
+
+// Register adds or replaces a named preview
+func (s *Server) Register(name string, build Builder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.builders[name] = build
+	s.revision++
+}
+
+// Reload tells open pages to refresh, e.g. after templates change on disk
+func (s *Server) Reload() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.revision++
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) version() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return strconv.FormatInt(s.started, 36) + "-" + strconv.Itoa(s.revision)
+}
+
+func (s *Server) names() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	names := make([]string, 0, len(s.builders))
+	for name := range s.builders {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func (s *Server) builder(name string) (Builder, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	build, ok := s.builders[name]
+	return build, ok
+}
+
+type pagePreview struct {
+	Name string
+	HTML template.HTML
+}
+
+type pageData struct {
+	Title      string
+	Stylesheet template.CSS
+	Version    string
+	Previews   []pagePreview
+	Single     bool
+}
+
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	data := pageData{Title: "dmsg previews", Version: s.version()}
+	for _, name := range s.names() {
+		build, _ := s.builder(name)
+		data.Previews = append(data.Previews, pagePreview{name, renderBuilder(build)})
+	}
+	s.writePage(w, data)
+}
+
+func (s *Server) handlePreview(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	build, ok := s.builder(name)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	s.writePage(w, pageData{
+		Title:    name,
+		Version:  s.version(),
+		Previews: []pagePreview{{name, renderBuilder(build)}},
+		Single:   true,
+	})
+}
+
+func (s *Server) handleLive(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write([]byte(s.version()))
+}
+
+func (s *Server) writePage(w http.ResponseWriter, data pageData) {
+	data.Stylesheet = template.CSS(Stylesheet)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := pageTemplate.Execute(w, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func renderBuilder(build Builder) template.HTML {
+	var b strings.Builder
+	if err := RenderResponse(&b, build()); err != nil {
+		return template.HTML(`<div class="dmsg-unknown">` + template.HTMLEscapeString(err.Error()) + `</div>`)
+	}
+	return template.HTML(b.String())
+}
+
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>{{.Stylesheet}}
+.preview { margin-bottom: 40px; }
+.preview-name { color: #949ba4; font-size: 12px; text-transform: uppercase; letter-spacing: 0.02em; margin-bottom: 8px; }
+</style>
+</head>
+<body>
+{{if .Single}}<p><a href="/">&larr; All previews</a></p>{{end}}
+{{range .Previews}}<div class="preview">
+<div class="preview-name"><a href="/preview/{{.Name}}">{{.Name}}</a></div>
+{{.HTML}}
+</div>
+{{else}}<p>No previews registered.</p>
+{{end}}
+<script>
+(function () {
+  var version = {{.Version}};
+  setInterval(function () {
+    fetch("/_live", {cache: "no-store"})
+      .then(function (r) { return r.text(); })
+      .then(function (v) { if (v !== version) location.reload(); })
+      .catch(function () {});
+  }, 1000);
+})();
+</script>
+</body>
+</html>
+`))
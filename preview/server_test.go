@@ -0,0 +1,94 @@
+package preview
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/thomasgtaylor/dmsg"
+)
+
+func get(t *testing.T, h http.Handler, path string) (int, string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	body, _ := io.ReadAll(rec.Result().Body)
+	return rec.Code, string(body)
+}
+
+func TestServer(t *testing.T) {
+	welcome := func() *discordgo.InteractionResponse {
+		return dmsg.Response(dmsg.TextDisplay("## Welcome"))
+	}
+
+	t.Run("lists registered previews on index", func(t *testing.T) {
+		srv := NewServer()
+		srv.Register("welcome", welcome)
+		srv.Register("about", welcome)
+
+		code, body := get(t, srv, "/")
+		if code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", code)
+		}
+
+		about := strings.Index(body, `href="/preview/about"`)
+		welcomeIdx := strings.Index(body, `href="/preview/welcome"`)
+		if about < 0 || welcomeIdx < 0 || about > welcomeIdx {
+			t.Errorf("expected sorted preview links, got %s", body)
+		}
+
+		if !strings.Contains(body, "<h2>Welcome</h2>") {
+			t.Errorf("expected rendered preview, got %s", body)
+		}
+	})
+
+	t.Run("serves single preview", func(t *testing.T) {
+		srv := NewServer()
+		srv.Register("welcome", welcome)
+
+		code, body := get(t, srv, "/preview/welcome")
+		if code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", code)
+		}
+
+		if !strings.Contains(body, "All previews") {
+			t.Errorf("expected back link, got %s", body)
+		}
+	})
+
+	t.Run("returns not found for unknown preview", func(t *testing.T) {
+		srv := NewServer()
+
+		code, _ := get(t, srv, "/preview/missing")
+		if code != http.StatusNotFound {
+			t.Errorf("expected status 404, got %d", code)
+		}
+	})
+
+	t.Run("changes live version on register and reload", func(t *testing.T) {
+		srv := NewServer()
+
+		_, v1 := get(t, srv, "/_live")
+		srv.Register("welcome", welcome)
+		_, v2 := get(t, srv, "/_live")
+		srv.Reload()
+		_, v3 := get(t, srv, "/_live")
+
+		if v1 == v2 || v2 == v3 {
+			t.Errorf("expected version to change, got %q, %q, %q", v1, v2, v3)
+		}
+	})
+
+	t.Run("embeds live version in page", func(t *testing.T) {
+		srv := NewServer()
+
+		_, version := get(t, srv, "/_live")
+		_, body := get(t, srv, "/")
+		if !strings.Contains(body, `var version = "`+version+`"`) {
+			t.Errorf("expected page to embed version %q, got %s", version, body)
+		}
+	})
+}
@@ -0,0 +1,157 @@
+package dmsg
+
+import (
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Split partitions components into as many messages as needed to stay within
+// MaxComponents and MaxTextLength. The first message is returned as a
+// standard response and the rest as follow-up messages, to be sent with
+// Session.FollowupMessageCreate.
+//
+// Oversized text displays are split on paragraph boundaries without breaking
+// code blocks, and oversized containers are split into several containers
+// with the same accent color and spoiler setting.
+func Split(components ...Component) (*discordgo.InteractionResponse, []*discordgo.WebhookParams) {
+	var units []Component
+	for _, c := range unwrapComponents(components) {
+		units = append(units, splitOversized(c)...)
+	}
+
+	var messages [][]Component
+	var current []Component
+	count, text := 0, 0
+	for _, unit := range units {
+		n, t := measure(unit)
+		if len(current) > 0 && (count+n > MaxComponents || text+t > MaxTextLength) {
+			messages = append(messages, current)
+			current, count, text = nil, 0, 0
+		}
+		current = append(current, unit)
+		count += n
+		text += t
+	}
+	messages = append(messages, current)
+
+	response := Response(messages[0]...)
+	followUps := make([]*discordgo.WebhookParams, 0, len(messages)-1)
+	for _, message := range messages[1:] {
+		followUps = append(followUps, &discordgo.WebhookParams{
+			Flags:      discordgo.MessageFlagsIsComponentsV2,
+			Components: message,
+			Files:      collectFiles(message),
+		})
+	}
+	return response, followUps
+}
+
+// measure returns the number of components and text characters in c,
+// including nested components
+func measure(c Component) (components, text int) {
+	components = 1
+	switch c := c.(type) {
+	case *discordgo.TextDisplay:
+		text = utf8.RuneCountInString(c.Content)
+	case *discordgo.Container:
+		for _, child := range c.Components {
+			n, t := measure(child)
+			components += n
+			text += t
+		}
+	case *discordgo.Section:
+		for _, child := range c.Components {
+			n, t := measure(child)
+			components += n
+			text += t
+		}
+		if c.Accessory != nil {
+			components++
+		}
+	case *discordgo.ActionsRow:
+		components += len(c.Components)
+	}
+	return components, text
+}
+
+func fits(c Component) bool {
+	n, t := measure(c)
+	return n <= MaxComponents && t <= MaxTextLength
+}
+
+// splitOversized breaks a component that cannot fit in a message on its own
+// into pieces that can
+func splitOversized(c Component) []Component {
+	if fits(c) {
+		return []Component{c}
+	}
+	switch c := c.(type) {
+	case *discordgo.TextDisplay:
+		parts := splitMarkdown(c.Content, MaxTextLength)
+		pieces := make([]Component, len(parts))
+		for i, part := range parts {
+			pieces[i] = &discordgo.TextDisplay{Content: part}
+		}
+		return pieces
+	case *discordgo.Section:
+		return splitSection(c)
+	case *discordgo.Container:
+		return splitContainer(c)
+	}
+	return []Component{c}
+}
+
+// splitSection keeps the accessory with the first part of the text and
+// moves the remaining text into top-level text displays
+func splitSection(s *discordgo.Section) []Component {
+	var parts []string
+	for _, child := range s.Components {
+		if text, ok := child.(*discordgo.TextDisplay); ok {
+			parts = append(parts, splitMarkdown(text.Content, MaxTextLength)...)
+		}
+	}
+	if len(parts) == 0 {
+		return []Component{s}
+	}
+	pieces := []Component{&discordgo.Section{
+		ID:         s.ID,
+		Components: []discordgo.MessageComponent{&discordgo.TextDisplay{Content: parts[0]}},
+		Accessory:  s.Accessory,
+	}}
+	for _, part := range parts[1:] {
+		pieces = append(pieces, &discordgo.TextDisplay{Content: part})
+	}
+	return pieces
+}
+
+// splitContainer distributes a container's children over as many copies of
+// the container as needed
+func splitContainer(c *discordgo.Container) []Component {
+	var children []Component
+	for _, child := range c.Components {
+		children = append(children, splitOversized(child)...)
+	}
+
+	newContainer := func() *discordgo.Container {
+		return &discordgo.Container{
+			AccentColor: c.AccentColor,
+			Spoiler:     c.Spoiler,
+			Components:  []discordgo.MessageComponent{},
+		}
+	}
+	var pieces []Component
+	current := newContainer()
+	count, text := 1, 0
+	for _, child := range children {
+		n, t := measure(child)
+		if len(current.Components) > 0 && (count+n > MaxComponents || text+t > MaxTextLength) {
+			pieces = append(pieces, current)
+			current, count, text = newContainer(), 1, 0
+		}
+		current.Components = append(current.Components, child)
+		count += n
+		text += t
+	}
+	return append(pieces, current)
+}
@@ -0,0 +1,136 @@
+package dmsg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func totals(components []Component) (count, text int) {
+	for _, c := range components {
+		n, t := measure(c)
+		count += n
+		text += t
+	}
+	return count, text
+}
+
+func TestSplit(t *testing.T) {
+	t.Run("keeps small messages whole", func(t *testing.T) {
+		response, followUps := Split(
+			TextDisplay("hello"),
+			Container(Section(TextDisplay("world"))),
+		)
+
+		if len(response.Data.Components) != 2 {
+			t.Errorf("expected 2 components, got %d", len(response.Data.Components))
+		}
+
+		if len(followUps) != 0 {
+			t.Errorf("expected no follow-ups, got %d", len(followUps))
+		}
+	})
+
+	t.Run("splits on component count", func(t *testing.T) {
+		var components []Component
+		for range 50 {
+			components = append(components, TextDisplay("line"))
+		}
+
+		response, followUps := Split(components...)
+
+		if len(response.Data.Components) != MaxComponents {
+			t.Errorf("expected %d components in first message, got %d", MaxComponents, len(response.Data.Components))
+		}
+
+		if len(followUps) != 1 || len(followUps[0].Components) != 10 {
+			t.Fatalf("expected 1 follow-up with 10 components, got %+v", followUps)
+		}
+
+		if followUps[0].Flags != discordgo.MessageFlagsIsComponentsV2 {
+			t.Errorf("expected components v2 flag, got %d", followUps[0].Flags)
+		}
+	})
+
+	t.Run("splits oversized text", func(t *testing.T) {
+		paragraph := strings.Repeat("x", 1500)
+		text := strings.Join([]string{paragraph, paragraph, paragraph, paragraph}, "\n\n")
+
+		response, followUps := Split(TextDisplay(text))
+
+		if len(followUps) != 1 {
+			t.Fatalf("expected 1 follow-up, got %d", len(followUps))
+		}
+
+		for _, message := range [][]Component{response.Data.Components, followUps[0].Components} {
+			if _, n := totals(message); n > MaxTextLength {
+				t.Errorf("message has %d characters", n)
+			}
+		}
+	})
+
+	t.Run("splits containers keeping accent color", func(t *testing.T) {
+		opts := []ContainerOption{AccentColor(42), Spoiler()}
+		for range 30 {
+			opts = append(opts, ActionRow(Button("a", "a")))
+		}
+
+		response, followUps := Split(Container(opts...))
+
+		messages := [][]Component{response.Data.Components}
+		for _, f := range followUps {
+			messages = append(messages, f.Components)
+		}
+
+		rows := 0
+		for _, message := range messages {
+			if n, _ := totals(message); n > MaxComponents {
+				t.Errorf("message has %d components", n)
+			}
+
+			for _, c := range message {
+				container := c.(*discordgo.Container)
+				if container.AccentColor == nil || *container.AccentColor != 42 || !container.Spoiler {
+					t.Error("expected container settings to be preserved")
+				}
+				rows += len(container.Components)
+			}
+		}
+
+		if rows != 30 {
+			t.Errorf("expected 30 action rows across messages, got %d", rows)
+		}
+	})
+
+	t.Run("keeps accessory with first part of section", func(t *testing.T) {
+		long := strings.Repeat("word ", 1000)
+		section := Section(TextDisplay(long), Accessory(Button("Go", "go")))
+
+		response, followUps := Split(section)
+
+		first, ok := response.Data.Components[0].(*discordgo.Section)
+		if !ok {
+			t.Fatal("expected first component to be *discordgo.Section")
+		}
+
+		if first.Accessory == nil {
+			t.Error("expected accessory to be kept")
+		}
+
+		if len(followUps) != 1 {
+			t.Errorf("expected 1 follow-up, got %d", len(followUps))
+		}
+	})
+
+	t.Run("does not modify input", func(t *testing.T) {
+		text := strings.Repeat("a\n\n", 3000)
+		td := TextDisplay(text)
+
+		Split(td)
+
+		if td.(textDisplayComponent).Content != text {
+			t.Error("expected input to be unchanged")
+		}
+	})
+}
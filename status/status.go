@@ -0,0 +1,258 @@
+// Package status builds the success, error, warning, info and loading
+// messages most handlers end with: an accented container with a heading, a
+// body, an optional icon and optional components below a separator.
+//
+//	dmsg.Response(
+//		status.Error("Error", "You don't have enough coins",
+//			status.Icon(errorImageURL, "Error icon"),
+//		),
+//	)
+package status
+
+import (
+	"context"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/thomasgtaylor/dmsg"
+)
+
+type kind int
+
+const (
+	successKind kind = iota
+	errorKind
+	warningKind
+	infoKind
+	loadingKind
+)
+
+// Default accent colors, used when neither an option nor a theme sets one
+var (
+	DefaultColors = dmsg.Palette{
+		Success: 0x57F287,
+		Error:   0xED4245,
+		Warning: 0xFEE75C,
+		Info:    0x5865F2,
+	}
+	DefaultLoadingColor = 0x99AAB5
+)
+
+// emojiNames are the names looked up in a theme's emoji set for each kind
+var emojiNames = map[kind]string{
+	successKind: "success",
+	errorKind:   "error",
+	warningKind: "warning",
+	infoKind:    "info",
+	loadingKind: "loading",
+}
+
+type message struct {
+	kind       kind
+	title      string
+	body       string
+	accent     int
+	emoji      *discordgo.ComponentEmoji
+	icon       dmsg.Component
+	components []dmsg.ContainerOption
+	ctx        context.Context
+}
+
+// Option configures a status message
+type Option interface {
+	applyToStatus(*message)
+}
+
+type accentOption struct {
+	color int
+}
+
+func (o accentOption) applyToStatus(m *message) {
+	m.accent = o.color
+}
+
+// Accent overrides the accent color
+func Accent(color int) Option {
+	return accentOption{color}
+}
+
+type iconOption struct {
+	icon dmsg.Component
+}
+
+func (o iconOption) applyToStatus(m *message) {
+	m.icon = o.icon
+}
+
+// Icon shows a thumbnail beside the heading and body
+func Icon(url, description string, opts ...dmsg.ThumbnailOption) Option {
+	return iconOption{dmsg.Thumbnail(url, description, opts...)}
+}
+
+type emojiOption struct {
+	emoji *discordgo.ComponentEmoji
+}
+
+func (o emojiOption) applyToStatus(m *message) {
+	m.emoji = o.emoji
+}
+
+// Emoji prefixes the heading with an emoji
+func Emoji(emoji *discordgo.ComponentEmoji) Option {
+	return emojiOption{emoji}
+}
+
+type withOption struct {
+	components []dmsg.ContainerOption
+}
+
+func (o withOption) applyToStatus(m *message) {
+	m.components = append(m.components, o.components...)
+}
+
+// With adds components, such as an action row, below a separator
+func With(components ...dmsg.ContainerOption) Option {
+	return withOption{components}
+}
+
+type themeOption struct {
+	ctx context.Context
+}
+
+func (o themeOption) applyToStatus(m *message) {
+	m.ctx = o.ctx
+}
+
+// Theme styles the message with the theme attached to ctx: its palette
+// color, its separator defaults and the emoji named "success", "error",
+// "warning", "info" or "loading" in its emoji set. Accent and Emoji still
+// take precedence.
+func Theme(ctx context.Context) Option {
+	return themeOption{ctx}
+}
+
+// Success creates a success message
+func Success(title, body string, opts ...Option) dmsg.Component {
+	return build(successKind, title, body, opts)
+}
+
+// Error creates an error message
+func Error(title, body string, opts ...Option) dmsg.Component {
+	return build(errorKind, title, body, opts)
+}
+
+// Warning creates a warning message
+func Warning(title, body string, opts ...Option) dmsg.Component {
+	return build(warningKind, title, body, opts)
+}
+
+// Info creates an informational message
+func Info(title, body string, opts ...Option) dmsg.Component {
+	return build(infoKind, title, body, opts)
+}
+
+// Loading creates a message for work still in progress, usually replaced
+// later with Update
+func Loading(title, body string, opts ...Option) dmsg.Component {
+	return build(loadingKind, title, body, opts)
+}
+
+func build(k kind, title, body string, opts []Option) dmsg.Component {
+	m := &message{kind: k, title: title, body: body}
+	for _, opt := range opts {
+		opt.applyToStatus(m)
+	}
+
+	var theme *dmsg.Theme
+	if m.ctx != nil {
+		theme = dmsg.ThemeFrom(m.ctx)
+	}
+	if m.accent == 0 {
+		m.accent = accentFor(k, theme)
+	}
+	if m.emoji == nil && theme != nil && theme.Emojis != nil {
+		m.emoji, _ = theme.Emojis.Get(emojiNames[k])
+	}
+
+	text := dmsg.TextDisplay(m.text())
+	containerOpts := []dmsg.ContainerOption{dmsg.AccentColor(m.accent)}
+	if m.icon != nil {
+		containerOpts = append(containerOpts, dmsg.Section(text, dmsg.Accessory(m.icon)))
+	} else {
+		containerOpts = append(containerOpts, text)
+	}
+	if len(m.components) > 0 {
+		var separatorOpts []dmsg.SeparatorOption
+		if m.ctx != nil {
+			separatorOpts = append(separatorOpts, dmsg.Themed(m.ctx))
+		}
+		containerOpts = append(containerOpts, dmsg.Separator(separatorOpts...))
+		containerOpts = append(containerOpts, m.components...)
+	}
+	return dmsg.Container(containerOpts...)
+}
+
+// text renders the heading and body as markdown
+func (m *message) text() string {
+	heading := m.title
+	if m.emoji != nil {
+		heading = emojiText(m.emoji) + " " + heading
+	}
+	switch {
+	case m.title == "":
+		return m.body
+	case m.body == "":
+		return "## " + heading
+	}
+	return "## " + heading + "\n\n" + m.body
+}
+
+func accentFor(k kind, theme *dmsg.Theme) int {
+	palette := DefaultColors
+	loading := DefaultLoadingColor
+	if theme != nil {
+		palette = mergePalette(theme.Colors, palette)
+		if theme.Accent != 0 {
+			loading = theme.Accent
+		}
+	}
+	switch k {
+	case successKind:
+		return palette.Success
+	case errorKind:
+		return palette.Error
+	case warningKind:
+		return palette.Warning
+	case infoKind:
+		return palette.Info
+	}
+	return loading
+}
+
+// mergePalette fills the unset colors of p from defaults
+func mergePalette(p, defaults dmsg.Palette) dmsg.Palette {
+	if p.Success == 0 {
+		p.Success = defaults.Success
+	}
+	if p.Error == 0 {
+		p.Error = defaults.Error
+	}
+	if p.Warning == 0 {
+		p.Warning = defaults.Warning
+	}
+	if p.Info == 0 {
+		p.Info = defaults.Info
+	}
+	return p
+}
+
+// emojiText writes an emoji the way Discord markdown expects it
+func emojiText(e *discordgo.ComponentEmoji) string {
+	if e.ID == "" {
+		return e.Name
+	}
+	prefix := "<:"
+	if e.Animated {
+		prefix = "<a:"
+	}
+	return prefix + e.Name + ":" + e.ID + ">"
+}
@@ -0,0 +1,136 @@
+package status
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/thomasgtaylor/dmsg"
+)
+
+func container(t *testing.T, c dmsg.Component) *discordgo.Container {
+	t.Helper()
+	response := dmsg.Response(c)
+	if err := dmsg.Validate(response); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+	return response.Data.Components[0].(*discordgo.Container)
+}
+
+func TestConstructors(t *testing.T) {
+	tests := []struct {
+		name   string
+		build  func(title, body string, opts ...Option) dmsg.Component
+		accent int
+	}{
+		{"success", Success, DefaultColors.Success},
+		{"error", Error, DefaultColors.Error},
+		{"warning", Warning, DefaultColors.Warning},
+		{"info", Info, DefaultColors.Info},
+		{"loading", Loading, DefaultLoadingColor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := container(t, tt.build("Title", "Body"))
+			if *c.AccentColor != tt.accent {
+				t.Errorf("expected accent %#x, got %#x", tt.accent, *c.AccentColor)
+			}
+			if len(c.Components) != 1 {
+				t.Fatalf("expected 1 component, got %d", len(c.Components))
+			}
+			text := c.Components[0].(*discordgo.TextDisplay)
+			if text.Content != "## Title\n\nBody" {
+				t.Errorf("unexpected content %q", text.Content)
+			}
+		})
+	}
+}
+
+func TestOptions(t *testing.T) {
+	t.Run("icon adds section with thumbnail", func(t *testing.T) {
+		c := container(t, Error("Error", "No coins", Icon("https://example.com/x.png", "Error icon")))
+		section, ok := c.Components[0].(*discordgo.Section)
+		if !ok {
+			t.Fatalf("expected section, got %T", c.Components[0])
+		}
+		thumbnail := section.Accessory.(*discordgo.Thumbnail)
+		if thumbnail.Media.URL != "https://example.com/x.png" {
+			t.Errorf("unexpected thumbnail URL %s", thumbnail.Media.URL)
+		}
+	})
+
+	t.Run("with adds components below separator", func(t *testing.T) {
+		c := container(t, Success("Done", "", With(dmsg.ActionRow(dmsg.Button("Undo", "undo")))))
+		if len(c.Components) != 3 {
+			t.Fatalf("expected 3 components, got %d", len(c.Components))
+		}
+		if _, ok := c.Components[1].(*discordgo.Separator); !ok {
+			t.Errorf("expected separator, got %T", c.Components[1])
+		}
+		if _, ok := c.Components[2].(*discordgo.ActionsRow); !ok {
+			t.Errorf("expected action row, got %T", c.Components[2])
+		}
+		if text := c.Components[0].(*discordgo.TextDisplay).Content; text != "## Done" {
+			t.Errorf("unexpected content %q", text)
+		}
+	})
+
+	t.Run("accent and emoji", func(t *testing.T) {
+		c := container(t, Info("", "Body", Accent(0x123456)))
+		if *c.AccentColor != 0x123456 {
+			t.Errorf("expected accent 0x123456, got %#x", *c.AccentColor)
+		}
+		if text := c.Components[0].(*discordgo.TextDisplay).Content; text != "Body" {
+			t.Errorf("unexpected content %q", text)
+		}
+
+		c = container(t, Warning("Careful", "Body", Emoji(&discordgo.ComponentEmoji{Name: "warn", ID: "9", Animated: true})))
+		if text := c.Components[0].(*discordgo.TextDisplay).Content; text != "## <a:warn:9> Careful\n\nBody" {
+			t.Errorf("unexpected content %q", text)
+		}
+	})
+}
+
+func TestTheme(t *testing.T) {
+	ctx := dmsg.WithTheme(context.Background(), &dmsg.Theme{
+		Accent:      0x111111,
+		Colors:      dmsg.Palette{Error: 0x222222},
+		Spacing:     discordgo.SeparatorSpacingSizeLarge,
+		HideDivider: true,
+		Emojis:      dmsg.NewEmojiRegistry(&discordgo.Emoji{ID: "1", Name: "error"}),
+	})
+
+	t.Run("uses palette, emoji and separator defaults", func(t *testing.T) {
+		c := container(t, Error("Failed", "Body", Theme(ctx), With(dmsg.TextDisplay("Details"))))
+		if *c.AccentColor != 0x222222 {
+			t.Errorf("expected accent 0x222222, got %#x", *c.AccentColor)
+		}
+		if text := c.Components[0].(*discordgo.TextDisplay).Content; text != "## <:error:1> Failed\n\nBody" {
+			t.Errorf("unexpected content %q", text)
+		}
+		separator := c.Components[1].(*discordgo.Separator)
+		if *separator.Divider || *separator.Spacing != discordgo.SeparatorSpacingSizeLarge {
+			t.Errorf("expected themed separator, got divider %v spacing %d", *separator.Divider, *separator.Spacing)
+		}
+	})
+
+	t.Run("falls back to defaults for unset colors", func(t *testing.T) {
+		c := container(t, Success("Done", "", Theme(ctx)))
+		if *c.AccentColor != DefaultColors.Success {
+			t.Errorf("expected accent %#x, got %#x", DefaultColors.Success, *c.AccentColor)
+		}
+
+		c = container(t, Loading("Working", "", Theme(ctx)))
+		if *c.AccentColor != 0x111111 {
+			t.Errorf("expected accent 0x111111, got %#x", *c.AccentColor)
+		}
+	})
+
+	t.Run("explicit accent wins", func(t *testing.T) {
+		c := container(t, Error("Failed", "", Accent(0x333333), Theme(ctx)))
+		if *c.AccentColor != 0x333333 {
+			t.Errorf("expected accent 0x333333, got %#x", *c.AccentColor)
+		}
+	})
+}
@@ -0,0 +1,327 @@
+package dmsg
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Alignment is the horizontal alignment of a table column
+type Alignment int
+
+const (
+	AlignLeft Alignment = iota
+	AlignRight
+	AlignCenter
+)
+
+// TableOption configures a Table
+type TableOption interface {
+	applyToTable(*table)
+}
+
+type table struct {
+	headers   []string
+	rows      [][]string
+	align     map[int]Alignment
+	maxWidths map[int]int
+}
+
+type headersOption struct {
+	headers []string
+}
+
+func (o headersOption) applyToTable(t *table) {
+	t.headers = o.headers
+}
+
+// Headers sets the table's column headers
+func Headers(headers ...string) TableOption {
+	return headersOption{headers}
+}
+
+type rowsOption struct {
+	rows [][]string
+}
+
+func (o rowsOption) applyToTable(t *table) {
+	t.rows = append(t.rows, o.rows...)
+}
+
+// Row adds a row of cells to the table
+func Row(cells ...string) TableOption {
+	return rowsOption{[][]string{cells}}
+}
+
+// Rows adds several rows of cells to the table
+func Rows(rows ...[]string) TableOption {
+	return rowsOption{rows}
+}
+
+type alignOption struct {
+	column    int
+	alignment Alignment
+}
+
+func (o alignOption) applyToTable(t *table) {
+	t.align[o.column] = o.alignment
+}
+
+// Align sets the alignment of a column, counted from zero
+func Align(column int, alignment Alignment) TableOption {
+	return alignOption{column, alignment}
+}
+
+type maxWidthOption struct {
+	column int
+	width  int
+}
+
+func (o maxWidthOption) applyToTable(t *table) {
+	t.maxWidths[o.column] = o.width
+}
+
+// MaxWidth limits the display width of a column, counted from zero. Longer
+// cells are truncated with an ellipsis.
+func MaxWidth(column, width int) TableOption {
+	return maxWidthOption{column, width}
+}
+
+// tableColumnGap separates table columns
+const tableColumnGap = "  "
+
+// Table renders rows as an aligned monospace table in a code block text
+// display. Column widths account for wide characters such as CJK and emoji.
+// A table longer than MaxTextLength is split into several text displays,
+// each repeating the headers, and columns too wide to fit are cut short.
+func Table(opts ...TableOption) Components {
+	t := &table{
+		align:     map[int]Alignment{},
+		maxWidths: map[int]int{},
+	}
+	for _, opt := range opts {
+		opt.applyToTable(t)
+	}
+
+	columns := len(t.headers)
+	for _, row := range t.rows {
+		columns = max(columns, len(row))
+	}
+	fit := func(cells []string) []string {
+		out := make([]string, columns)
+		for i := range out {
+			if i < len(cells) {
+				out[i] = strings.ReplaceAll(cells[i], "\n", " ")
+				// Break up runs of backticks so a cell cannot close the code
+				// block.
+				out[i] = strings.ReplaceAll(out[i], "``", "`\u200b`")
+			}
+			if limit, ok := t.maxWidths[i]; ok {
+				out[i] = truncateWidth(out[i], limit)
+			}
+		}
+		return out
+	}
+
+	headers := fit(t.headers)
+	rows := make([][]string, len(t.rows))
+	for i, row := range t.rows {
+		rows[i] = fit(row)
+	}
+
+	widths := make([]int, columns)
+	for _, row := range append([][]string{headers}, rows...) {
+		for i, cell := range row {
+			widths[i] = max(widths[i], stringWidth(cell))
+		}
+	}
+
+	const fence = "```\n"
+	const closing = "\n```"
+
+	// Narrow the widest columns until the headers and at least one row fit
+	// in a text display.
+	maxLine := (MaxTextLength-len(fence+closing))/3 - 1
+	if narrowColumns(widths, maxLine) {
+		for _, row := range append([][]string{headers}, rows...) {
+			for i := range row {
+				row[i] = truncateWidth(row[i], widths[i])
+			}
+		}
+	}
+
+	var head []string
+	if len(t.headers) > 0 {
+		rules := make([]string, columns)
+		for i, w := range widths {
+			rules[i] = strings.Repeat("-", w)
+		}
+		head = []string{t.formatRow(headers, widths), t.formatRow(rules, widths)}
+	}
+
+	var components Components
+	lines := append([]string{}, head...)
+	length := utf8.RuneCountInString(fence + closing)
+	for _, line := range head {
+		length += utf8.RuneCountInString(line) + 1
+	}
+	headLength := length
+	bodyLines := 0
+	for _, row := range rows {
+		line := t.formatRow(row, widths)
+		// Zero-width characters can still leave a row too long.
+		if budget := MaxTextLength - headLength - 1; utf8.RuneCountInString(line) > budget {
+			line = string([]rune(line)[:budget-1]) + "…"
+		}
+		n := utf8.RuneCountInString(line) + 1
+		if bodyLines > 0 && length+n > MaxTextLength {
+			components = append(components, TextDisplay(fence+strings.Join(lines, "\n")+closing))
+			lines = append([]string{}, head...)
+			length, bodyLines = headLength, 0
+		}
+		lines = append(lines, line)
+		length += n
+		bodyLines++
+	}
+	if len(lines) > 0 {
+		components = append(components, TextDisplay(fence+strings.Join(lines, "\n")+closing))
+	}
+	return components
+}
+
+// narrowColumns shrinks the widest columns until a row fits in maxLine
+// columns, reporting whether any changed
+func narrowColumns(widths []int, maxLine int) bool {
+	total := len(tableColumnGap) * (len(widths) - 1)
+	for _, w := range widths {
+		total += w
+	}
+	narrowed := false
+	for ; total > maxLine; total-- {
+		widest := 0
+		for i, w := range widths {
+			if w > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= 1 {
+			break
+		}
+		widths[widest]--
+		narrowed = true
+	}
+	return narrowed
+}
+
+func (t *table) formatRow(cells []string, widths []int) string {
+	padded := make([]string, len(cells))
+	for i, cell := range cells {
+		gap := widths[i] - stringWidth(cell)
+		switch t.align[i] {
+		case AlignRight:
+			padded[i] = strings.Repeat(" ", gap) + cell
+		case AlignCenter:
+			padded[i] = strings.Repeat(" ", gap/2) + cell + strings.Repeat(" ", gap-gap/2)
+		default:
+			padded[i] = cell + strings.Repeat(" ", gap)
+		}
+	}
+	return strings.TrimRight(strings.Join(padded, tableColumnGap), " ")
+}
+
+// truncateWidth shortens s to at most width columns, ending with an ellipsis
+func truncateWidth(s string, width int) string {
+	if stringWidth(s) <= width {
+		return s
+	}
+	if width <= 0 {
+		return ""
+	}
+	var b strings.Builder
+	used := 0
+	for _, r := range s {
+		w := runeWidth(r)
+		if used+w > width-1 {
+			break
+		}
+		b.WriteRune(r)
+		used += w
+	}
+	return b.String() + Ellipsis
+}
+
+// stringWidth returns the number of monospace columns s occupies
+func stringWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		width += runeWidth(r)
+	}
+	return width
+}
+
+// wideRanges are the code points displayed two columns wide: East Asian
+// wide and fullwidth characters, and emoji
+var wideRanges = []struct{ lo, hi rune }{
+	{0x1100, 0x115F},
+	{0x231A, 0x231B},
+	{0x23E9, 0x23EC},
+	{0x25FD, 0x25FE},
+	{0x2614, 0x2615},
+	{0x2648, 0x2653},
+	{0x26A1, 0x26A1},
+	{0x26AA, 0x26AB},
+	{0x26BD, 0x26BE},
+	{0x26C4, 0x26C5},
+	{0x26D4, 0x26D4},
+	{0x26EA, 0x26EA},
+	{0x26F2, 0x26F5},
+	{0x26FA, 0x26FD},
+	{0x2705, 0x2705},
+	{0x270A, 0x270B},
+	{0x2728, 0x2728},
+	{0x274C, 0x274C},
+	{0x2753, 0x2757},
+	{0x2795, 0x2797},
+	{0x27B0, 0x27B0},
+	{0x2B1B, 0x2B1C},
+	{0x2B50, 0x2B55},
+	{0x2E80, 0x303E},
+	{0x3041, 0x33FF},
+	{0x3400, 0x4DBF},
+	{0x4E00, 0x9FFF},
+	{0xA000, 0xA4CF},
+	{0xAC00, 0xD7A3},
+	{0xF900, 0xFAFF},
+	{0xFE30, 0xFE4F},
+	{0xFF00, 0xFF60},
+	{0xFFE0, 0xFFE6},
+	{0x1F004, 0x1F004},
+	{0x1F0CF, 0x1F0CF},
+	{0x1F18E, 0x1F18E},
+	{0x1F191, 0x1F19A},
+	{0x1F200, 0x1F2FF},
+	{0x1F300, 0x1F64F},
+	{0x1F680, 0x1F6FF},
+	{0x1F7E0, 0x1F7EB},
+	{0x1F900, 0x1F9FF},
+	{0x1FA70, 0x1FAFF},
+	{0x20000, 0x3FFFD},
+}
+
+// runeWidth returns the number of monospace columns r occupies
+func runeWidth(r rune) int {
+	// Combining marks, format characters and emoji skin tone modifiers
+	// attach to the preceding character.
+	if unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) || r >= 0x1F3FB && r <= 0x1F3FF {
+		return 0
+	}
+	for _, w := range wideRanges {
+		if r < w.lo {
+			break
+		}
+		if r <= w.hi {
+			return 2
+		}
+	}
+	return 1
+}
@@ -0,0 +1,178 @@
+package dmsg
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func tableContent(t *testing.T, components Components) []string {
+	t.Helper()
+	out := make([]string, len(components))
+	for i, c := range components {
+		td, ok := c.(textDisplayComponent)
+		if !ok {
+			t.Fatal("expected textDisplayComponent")
+		}
+		out[i] = td.Content
+	}
+	return out
+}
+
+func TestTable(t *testing.T) {
+	t.Run("renders aligned code block", func(t *testing.T) {
+		got := tableContent(t, Table(
+			Headers("Player", "Score"),
+			Row("Alice", "120"),
+			Row("Bob", "7"),
+			Align(1, AlignRight),
+		))
+
+		expected := "```\n" +
+			"Player  Score\n" +
+			"------  -----\n" +
+			"Alice     120\n" +
+			"Bob         7\n" +
+			"```"
+		if len(got) != 1 || got[0] != expected {
+			t.Errorf("expected %q, got %q", expected, got)
+		}
+	})
+
+	t.Run("centers columns", func(t *testing.T) {
+		got := tableContent(t, Table(
+			Headers("Rank", "X"),
+			Rows([]string{"1", "ok"}),
+			Align(0, AlignCenter),
+		))
+
+		expected := "```\nRank  X\n----  --\n 1    ok\n```"
+		if got[0] != expected {
+			t.Errorf("expected %q, got %q", expected, got[0])
+		}
+	})
+
+	t.Run("accounts for wide characters", func(t *testing.T) {
+		got := tableContent(t, Table(
+			Headers("Name", "Pts"),
+			Row("日本", "1"),
+			Row("ab", "2"),
+		))
+
+		lines := strings.Split(got[0], "\n")
+		if lines[3] != "日本  1" || lines[4] != "ab    2" {
+			t.Errorf("unexpected rows %q", lines[3:5])
+		}
+	})
+
+	t.Run("limits column width", func(t *testing.T) {
+		got := tableContent(t, Table(
+			Row("a very long cell", "x"),
+			MaxWidth(0, 6),
+		))
+
+		if got[0] != "```\na ver…  x\n```" {
+			t.Errorf("unexpected content %q", got[0])
+		}
+	})
+
+	t.Run("pads short rows", func(t *testing.T) {
+		got := tableContent(t, Table(
+			Headers("A", "B", "C"),
+			Row("1"),
+		))
+
+		if !strings.Contains(got[0], "\n1\n") {
+			t.Errorf("unexpected content %q", got[0])
+		}
+	})
+
+	t.Run("splits long tables repeating headers", func(t *testing.T) {
+		var rows [][]string
+		for i := range 300 {
+			rows = append(rows, []string{fmt.Sprintf("player-%03d", i), strings.Repeat("9", 10)})
+		}
+
+		got := tableContent(t, Table(Headers("Player", "Score"), Rows(rows...)))
+
+		if len(got) < 2 {
+			t.Fatalf("expected table to be split, got %d parts", len(got))
+		}
+
+		total := 0
+		for _, part := range got {
+			if n := utf8.RuneCountInString(part); n > MaxTextLength {
+				t.Errorf("part has %d characters", n)
+			}
+
+			if !strings.HasPrefix(part, "```\nPlayer") || !strings.HasSuffix(part, "\n```") {
+				t.Errorf("expected fenced part with headers, got %q", part[:20])
+			}
+			total += strings.Count(part, "player-")
+		}
+
+		if total != 300 {
+			t.Errorf("expected 300 rows, got %d", total)
+		}
+	})
+
+	t.Run("keeps fences in cells from closing the block", func(t *testing.T) {
+		got := tableContent(t, Table(Headers("Code"), Row("```go"), Row("x")))
+
+		if strings.Count(got[0], "```") != 2 {
+			t.Errorf("expected only the table's fences, got %q", got[0])
+		}
+	})
+
+	t.Run("cuts columns too wide for a text display", func(t *testing.T) {
+		got := tableContent(t, Table(Headers("Text"), Row(strings.Repeat("x", 5000)), Row("short")))
+
+		for _, part := range got {
+			if n := utf8.RuneCountInString(part); n > MaxTextLength {
+				t.Errorf("part has %d characters", n)
+			}
+		}
+		if !strings.Contains(got[0], "x…\n") || !strings.Contains(got[len(got)-1], "short") {
+			t.Errorf("expected cut row followed by short row, got %d parts", len(got))
+		}
+	})
+
+	t.Run("works in containers", func(t *testing.T) {
+		container := Container(Table(Headers("A"), Row("1")))
+
+		c := container.(*discordgo.Container)
+		if len(c.Components) != 1 {
+			t.Fatalf("expected 1 component, got %d", len(c.Components))
+		}
+	})
+
+	t.Run("renders nothing for empty table", func(t *testing.T) {
+		if got := Table(); len(got) != 0 {
+			t.Errorf("expected no components, got %d", len(got))
+		}
+	})
+}
+
+func TestStringWidth(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"abc", 3},
+		{"日本語", 6},
+		{"한글", 4},
+		{"🔥", 2},
+		{"é", 1},
+		{"👍🏽", 2},
+		{"ｆｕｌｌ", 8},
+	}
+
+	for _, tt := range tests {
+		if got := stringWidth(tt.s); got != tt.want {
+			t.Errorf("stringWidth(%q): expected %d, got %d", tt.s, tt.want, got)
+		}
+	}
+}
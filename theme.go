@@ -0,0 +1,118 @@
+package dmsg
+
+import (
+	"context"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Theme holds branding defaults shared by a bot's messages. Attach it to a
+// context with WithTheme and pass Themed(ctx) to Container, Separator and
+// Button; explicit options always take precedence over the theme, wherever
+// they appear in the option list. Zero fields leave dmsg's own defaults.
+type Theme struct {
+	// Accent is the default container accent color
+	Accent int
+	// Colors are the accent colors for status messages
+	Colors Palette
+	// Spacing is the default separator spacing
+	Spacing discordgo.SeparatorSpacingSize
+	// HideDivider hides separator divider lines by default
+	HideDivider bool
+	// ButtonStyle is the default style for action buttons
+	ButtonStyle ButtonStyle
+	// Emojis resolves emoji names used with the theme
+	Emojis *EmojiRegistry
+}
+
+// Palette holds accent colors for each kind of status message
+type Palette struct {
+	Success int
+	Error   int
+	Warning int
+	Info    int
+}
+
+// Emoji sets the button emoji from the theme's emoji set, falling back to
+// ParseEmoji when the theme has none
+func (t *Theme) Emoji(name string) ButtonOption {
+	if t == nil || t.Emojis == nil {
+		return registryEmojiOption{NewEmojiRegistry(), name}
+	}
+	return t.Emojis.Emoji(name)
+}
+
+type themeKey struct{}
+
+// WithTheme returns a copy of ctx carrying theme
+func WithTheme(ctx context.Context, theme *Theme) context.Context {
+	return context.WithValue(ctx, themeKey{}, theme)
+}
+
+// ThemeFrom returns the theme attached to ctx, or nil
+func ThemeFrom(ctx context.Context) *Theme {
+	theme, _ := ctx.Value(themeKey{}).(*Theme)
+	return theme
+}
+
+type themeOption struct {
+	theme *Theme
+}
+
+func (o themeOption) applyToContainer(c *discordgo.Container) {
+	if o.theme != nil && o.theme.Accent != 0 {
+		accent := o.theme.Accent
+		c.AccentColor = &accent
+	}
+}
+
+func (o themeOption) applyToSeparator(s *discordgo.Separator) {
+	if o.theme == nil {
+		return
+	}
+	if o.theme.Spacing != 0 {
+		spacing := o.theme.Spacing
+		s.Spacing = &spacing
+	}
+	if o.theme.HideDivider {
+		divider := false
+		s.Divider = &divider
+	}
+}
+
+func (o themeOption) applyToButton(b *discordgo.Button) {
+	// Link and premium buttons have fixed styles.
+	if o.theme != nil && o.theme.ButtonStyle != 0 && b.Style == discordgo.PrimaryButton {
+		b.Style = discordgo.ButtonStyle(o.theme.ButtonStyle)
+	}
+}
+
+// Themed applies the defaults of the theme attached to ctx. It does nothing
+// if ctx has no theme.
+func Themed(ctx context.Context) interface {
+	ContainerOption
+	SeparatorOption
+	ButtonOption
+} {
+	return themeOption{ThemeFrom(ctx)}
+}
+
+// defaultsFirst moves theme options to the front so explicit options
+// override them
+func defaultsFirst[O any](opts []O) []O {
+	sorted := make([]O, 0, len(opts))
+	for _, opt := range opts {
+		if _, ok := any(opt).(themeOption); ok {
+			sorted = append(sorted, opt)
+		}
+	}
+	if len(sorted) == 0 {
+		return opts
+	}
+	for _, opt := range opts {
+		if _, ok := any(opt).(themeOption); !ok {
+			sorted = append(sorted, opt)
+		}
+	}
+	return sorted
+}
@@ -0,0 +1,99 @@
+package dmsg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestTheme(t *testing.T) {
+	theme := &Theme{
+		Accent:      0x5865F2,
+		Spacing:     discordgo.SeparatorSpacingSizeLarge,
+		HideDivider: true,
+		ButtonStyle: Secondary,
+		Emojis:      NewEmojiRegistry(&discordgo.Emoji{ID: "1", Name: "brand"}),
+	}
+	ctx := WithTheme(context.Background(), theme)
+
+	t.Run("returns attached theme", func(t *testing.T) {
+		if ThemeFrom(ctx) != theme {
+			t.Error("expected attached theme")
+		}
+		if ThemeFrom(context.Background()) != nil {
+			t.Error("expected no theme")
+		}
+	})
+
+	t.Run("applies container accent", func(t *testing.T) {
+		container := Container(Themed(ctx)).(*discordgo.Container)
+		if container.AccentColor == nil || *container.AccentColor != 0x5865F2 {
+			t.Errorf("expected accent 0x5865F2, got %v", container.AccentColor)
+		}
+	})
+
+	t.Run("explicit options override theme in any order", func(t *testing.T) {
+		container := Container(AccentColor(0xFF0000), Themed(ctx)).(*discordgo.Container)
+		if *container.AccentColor != 0xFF0000 {
+			t.Errorf("expected accent 0xFF0000, got %#x", *container.AccentColor)
+		}
+
+		button := Button("OK", "ok", Style(Danger), Themed(ctx)).(*discordgo.Button)
+		if button.Style != discordgo.DangerButton {
+			t.Errorf("expected danger style, got %d", button.Style)
+		}
+
+		separator := Separator(Themed(ctx), WithDivider(true)).(separatorComponent)
+		if !*separator.Divider {
+			t.Error("expected divider")
+		}
+	})
+
+	t.Run("applies separator defaults", func(t *testing.T) {
+		separator := Separator(Themed(ctx)).(separatorComponent)
+		if *separator.Divider {
+			t.Error("expected hidden divider")
+		}
+		if *separator.Spacing != discordgo.SeparatorSpacingSizeLarge {
+			t.Errorf("expected large spacing, got %d", *separator.Spacing)
+		}
+	})
+
+	t.Run("applies button style to action buttons only", func(t *testing.T) {
+		button := Button("OK", "ok", Themed(ctx)).(*discordgo.Button)
+		if button.Style != discordgo.SecondaryButton {
+			t.Errorf("expected secondary style, got %d", button.Style)
+		}
+
+		link := LinkButton("Docs", "https://example.com", Themed(ctx)).(*discordgo.Button)
+		if link.Style != discordgo.LinkButton {
+			t.Errorf("expected link style, got %d", link.Style)
+		}
+	})
+
+	t.Run("resolves emoji from theme", func(t *testing.T) {
+		button := Button("OK", "ok", theme.Emoji("brand")).(*discordgo.Button)
+		if button.Emoji == nil || button.Emoji.ID != "1" {
+			t.Errorf("expected brand emoji, got %v", button.Emoji)
+		}
+
+		var none *Theme
+		button = Button("OK", "ok", none.Emoji(":fire:")).(*discordgo.Button)
+		if button.Emoji == nil || button.Emoji.Name != "🔥" {
+			t.Errorf("expected fire emoji, got %v", button.Emoji)
+		}
+	})
+
+	t.Run("does nothing without a theme", func(t *testing.T) {
+		container := Container(Themed(context.Background())).(*discordgo.Container)
+		if container.AccentColor != nil {
+			t.Errorf("expected no accent, got %v", *container.AccentColor)
+		}
+
+		button := Button("OK", "ok", Themed(context.Background())).(*discordgo.Button)
+		if button.Style != discordgo.PrimaryButton {
+			t.Errorf("expected primary style, got %d", button.Style)
+		}
+	})
+}
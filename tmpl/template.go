@@ -0,0 +1,769 @@
+// Package tmpl loads dmsg messages from declarative YAML or JSON documents,
+// so messages can be edited without touching Go code.
+//
+// A document lists components using the same vocabulary as dmsg. Every
+// string value is a text/template, filled from a data value at render time:
+//
+//	ephemeral: false
+//	components:
+//	  - container:
+//	      accent_color: "#57F287"
+//	      components:
+//	        - section:
+//	            text:
+//	              - "## Welcome, {{.Name}}!"
+//	            accessory:
+//	              thumbnail:
+//	                url: "{{.AvatarURL}}"
+//	                description: "{{.Name}}'s avatar"
+//	        - separator: {spacing: large}
+//	        - actions:
+//	            - button: {label: Accept, custom_id: "accept:{{.ID}}", style: success}
+//	            - link: {label: Rules, url: "https://example.com/rules"}
+//
+// JSON documents use the same structure. Errors report the document name and
+// line of the offending element.
+package tmpl
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/thomasgtaylor/dmsg"
+	"gopkg.in/yaml.v3"
+)
+
+// Template is a parsed message document
+type Template struct {
+	name       string
+	funcs      template.FuncMap
+	emojis     *dmsg.EmojiRegistry
+	ephemeral  bool
+	components []builder
+}
+
+// Error describes a problem in a document, at parse or render time
+type Error struct {
+	Name string
+	Line int
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.Name, e.Line, e.Msg)
+}
+
+// New allocates an empty template with the given name, used in errors
+func New(name string) *Template {
+	return &Template{name: name}
+}
+
+// Funcs adds functions available to the text templates in the document. It
+// must be called before Parse.
+func (t *Template) Funcs(funcs template.FuncMap) *Template {
+	if t.funcs == nil {
+		t.funcs = template.FuncMap{}
+	}
+	for name, fn := range funcs {
+		t.funcs[name] = fn
+	}
+	return t
+}
+
+// Emojis resolves emoji names in the document with registry, so buttons can
+// use "emoji: name" for custom emojis. It must be called before Parse.
+func (t *Template) Emojis(registry *dmsg.EmojiRegistry) *Template {
+	t.emojis = registry
+	return t
+}
+
+// Parse parses a YAML or JSON document into the template
+func (t *Template) Parse(src []byte) (*Template, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(src, &doc); err != nil {
+		return nil, t.yamlError(err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, &Error{t.name, 1, "empty document"}
+	}
+
+	p := parser{name: t.name, funcs: t.funcs, emojis: t.emojis}
+	fields, err := p.fields(doc.Content[0], "document", "ephemeral", "components")
+	if err != nil {
+		return nil, err
+	}
+	if n, ok := fields["ephemeral"]; ok {
+		if t.ephemeral, err = p.bool(n); err != nil {
+			return nil, err
+		}
+	}
+	n, ok := fields["components"]
+	if !ok {
+		return nil, p.errorf(doc.Content[0], "document has no components")
+	}
+	if t.components, err = p.list(n); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+var yamlLinePattern = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// yamlError converts an error from the YAML parser into an *Error, taking
+// the line from its message
+func (t *Template) yamlError(err error) error {
+	msg := err.Error()
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) && len(typeErr.Errors) > 0 {
+		msg = typeErr.Errors[0]
+	}
+	line := 1
+	if m := yamlLinePattern.FindStringSubmatch(msg); m != nil {
+		line, _ = strconv.Atoi(m[1])
+		msg = m[2]
+	} else {
+		msg = strings.TrimPrefix(msg, "yaml: ")
+	}
+	return &Error{t.name, line, msg}
+}
+
+// Parse parses a YAML or JSON document into a new template
+func Parse(name string, src []byte) (*Template, error) {
+	return New(name).Parse(src)
+}
+
+// ParseFile reads and parses a YAML or JSON document, named after the file
+func ParseFile(path string) (*Template, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return New(filepath.Base(path)).Parse(src)
+}
+
+// Must panics if err is non-nil, for templates loaded at init time
+func Must(t *Template, err error) *Template {
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Name returns the template's name
+func (t *Template) Name() string {
+	return t.name
+}
+
+// Components renders the document's top-level components with data
+func (t *Template) Components(data any) ([]dmsg.Component, error) {
+	components := make([]dmsg.Component, 0, len(t.components))
+	for _, build := range t.components {
+		p, err := build(data)
+		if err != nil {
+			return nil, err
+		}
+		components = append(components, p.component)
+	}
+	return components, nil
+}
+
+// Response renders the document as an interaction response, ephemeral if the
+// document says so
+func (t *Template) Response(data any) (*discordgo.InteractionResponse, error) {
+	components, err := t.Components(data)
+	if err != nil {
+		return nil, err
+	}
+	if t.ephemeral {
+		return dmsg.Ephemeral(components...), nil
+	}
+	return dmsg.Response(components...), nil
+}
+
+// part is a rendered element: the component used at the top level and the
+// option that adds it to a container, which containers do not have
+type part struct {
+	component dmsg.Component
+	option    dmsg.ContainerOption
+}
+
+type builder func(data any) (part, error)
+
+// text is a templated string value
+type text struct {
+	name string
+	line int
+	tmpl *template.Template
+}
+
+func (s text) render(data any) (string, error) {
+	var b bytes.Buffer
+	if err := s.tmpl.Execute(&b, data); err != nil {
+		return "", &Error{s.name, s.line, err.Error()}
+	}
+	return b.String(), nil
+}
+
+type parser struct {
+	name   string
+	funcs  template.FuncMap
+	emojis *dmsg.EmojiRegistry
+}
+
+func (p parser) errorf(n *yaml.Node, format string, args ...any) error {
+	return &Error{p.name, n.Line, fmt.Sprintf(format, args...)}
+}
+
+// fields checks that n is a mapping with only the allowed keys
+func (p parser) fields(n *yaml.Node, what string, allowed ...string) (map[string]*yaml.Node, error) {
+	if n.Kind != yaml.MappingNode {
+		return nil, p.errorf(n, "%s must be a mapping", what)
+	}
+	fields := map[string]*yaml.Node{}
+	for i := 0; i < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		known := false
+		for _, a := range allowed {
+			known = known || key.Value == a
+		}
+		if !known {
+			return nil, p.errorf(key, "unknown field %q in %s (expected one of %s)", key.Value, what, strings.Join(allowed, ", "))
+		}
+		if _, dup := fields[key.Value]; dup {
+			return nil, p.errorf(key, "duplicate field %q in %s", key.Value, what)
+		}
+		fields[key.Value] = value
+	}
+	return fields, nil
+}
+
+func (p parser) text(n *yaml.Node) (text, error) {
+	if n.Kind != yaml.ScalarNode {
+		return text{}, p.errorf(n, "expected a string")
+	}
+	t, err := template.New(p.name).Funcs(p.funcs).Option("missingkey=error").Parse(n.Value)
+	if err != nil {
+		return text{}, p.errorf(n, "%v", err)
+	}
+	return text{p.name, n.Line, t}, nil
+}
+
+func (p parser) optionalText(fields map[string]*yaml.Node, key string) (*text, error) {
+	n, ok := fields[key]
+	if !ok {
+		return nil, nil
+	}
+	t, err := p.text(n)
+	return &t, err
+}
+
+func (p parser) requiredText(n *yaml.Node, fields map[string]*yaml.Node, key, what string) (text, error) {
+	v, ok := fields[key]
+	if !ok {
+		return text{}, p.errorf(n, "%s requires %s", what, key)
+	}
+	return p.text(v)
+}
+
+func (p parser) bool(n *yaml.Node) (bool, error) {
+	var v bool
+	if n.Kind != yaml.ScalarNode || n.Decode(&v) != nil {
+		return false, p.errorf(n, "expected true or false, got %q", n.Value)
+	}
+	return v, nil
+}
+
+func (p parser) optionalBool(fields map[string]*yaml.Node, key string) (bool, error) {
+	n, ok := fields[key]
+	if !ok {
+		return false, nil
+	}
+	return p.bool(n)
+}
+
+// color accepts an integer or a "#RRGGBB" string
+func (p parser) color(n *yaml.Node) (int, error) {
+	if n.Kind == yaml.ScalarNode {
+		if hex, ok := strings.CutPrefix(n.Value, "#"); ok {
+			if v, err := strconv.ParseUint(hex, 16, 24); err == nil && len(hex) == 6 {
+				return int(v), nil
+			}
+		} else if v, err := strconv.ParseInt(n.Value, 0, 32); err == nil && v >= 0 && v <= 0xFFFFFF {
+			return int(v), nil
+		}
+	}
+	return 0, p.errorf(n, "invalid color %q (expected an integer or #RRGGBB)", n.Value)
+}
+
+// element returns the kind and body of a list entry, which is either a
+// bare kind ("- separator") or a single-key mapping ("- text: hello")
+func (p parser) element(n *yaml.Node) (string, *yaml.Node, error) {
+	switch {
+	case n.Kind == yaml.ScalarNode && n.Tag == "!!str":
+		return n.Value, nil, nil
+	case n.Kind == yaml.MappingNode && len(n.Content) == 2:
+		return n.Content[0].Value, n.Content[1], nil
+	}
+	return "", nil, p.errorf(n, "expected a component such as text, section or container")
+}
+
+func (p parser) list(n *yaml.Node) ([]builder, error) {
+	if n.Kind != yaml.SequenceNode {
+		return nil, p.errorf(n, "components must be a list")
+	}
+	builders := make([]builder, 0, len(n.Content))
+	for _, item := range n.Content {
+		b, err := p.component(item)
+		if err != nil {
+			return nil, err
+		}
+		builders = append(builders, b)
+	}
+	return builders, nil
+}
+
+func (p parser) component(n *yaml.Node) (builder, error) {
+	kind, body, err := p.element(n)
+	if err != nil {
+		return nil, err
+	}
+	if body == nil && kind != "separator" {
+		return nil, p.errorf(n, "%s needs a value", kind)
+	}
+	switch kind {
+	case "container":
+		return p.container(body)
+	case "section":
+		return p.section(body)
+	case "text":
+		return p.textDisplay(body)
+	case "separator":
+		return p.separator(n, body)
+	case "actions":
+		return p.actions(body)
+	case "gallery":
+		return p.gallery(body)
+	case "file":
+		return p.file(body)
+	}
+	return nil, p.errorf(n, "unknown component %q", kind)
+}
+
+func (p parser) container(n *yaml.Node) (builder, error) {
+	fields, err := p.fields(n, "container", "accent_color", "spoiler", "components")
+	if err != nil {
+		return nil, err
+	}
+	var static []dmsg.ContainerOption
+	if c, ok := fields["accent_color"]; ok {
+		color, err := p.color(c)
+		if err != nil {
+			return nil, err
+		}
+		static = append(static, dmsg.AccentColor(color))
+	}
+	if spoiler, err := p.optionalBool(fields, "spoiler"); err != nil {
+		return nil, err
+	} else if spoiler {
+		static = append(static, dmsg.Spoiler())
+	}
+	var children []builder
+	if c, ok := fields["components"]; ok {
+		for _, item := range c.Content {
+			if kind, _, err := p.element(item); err == nil && kind == "container" {
+				return nil, p.errorf(item, "containers cannot be nested")
+			}
+		}
+		if children, err = p.list(c); err != nil {
+			return nil, err
+		}
+	}
+	return func(data any) (part, error) {
+		opts := append([]dmsg.ContainerOption{}, static...)
+		for _, build := range children {
+			child, err := build(data)
+			if err != nil {
+				return part{}, err
+			}
+			opts = append(opts, child.option)
+		}
+		c := dmsg.Container(opts...)
+		return part{c, nil}, nil
+	}, nil
+}
+
+func (p parser) section(n *yaml.Node) (builder, error) {
+	fields, err := p.fields(n, "section", "text", "accessory")
+	if err != nil {
+		return nil, err
+	}
+	var texts []text
+	if t, ok := fields["text"]; ok {
+		items := []*yaml.Node{t}
+		if t.Kind == yaml.SequenceNode {
+			items = t.Content
+		}
+		for _, item := range items {
+			tx, err := p.text(item)
+			if err != nil {
+				return nil, err
+			}
+			texts = append(texts, tx)
+		}
+	}
+	var accessory func(data any) (dmsg.Component, error)
+	if a, ok := fields["accessory"]; ok {
+		if accessory, err = p.accessory(a); err != nil {
+			return nil, err
+		}
+	}
+	return func(data any) (part, error) {
+		opts := make([]dmsg.SectionOption, 0, len(texts)+1)
+		for _, t := range texts {
+			content, err := t.render(data)
+			if err != nil {
+				return part{}, err
+			}
+			opts = append(opts, dmsg.TextDisplay(content))
+		}
+		if accessory != nil {
+			c, err := accessory(data)
+			if err != nil {
+				return part{}, err
+			}
+			opts = append(opts, dmsg.Accessory(c))
+		}
+		s := dmsg.Section(opts...)
+		return part{s, s}, nil
+	}, nil
+}
+
+func (p parser) accessory(n *yaml.Node) (func(data any) (dmsg.Component, error), error) {
+	kind, body, err := p.element(n)
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		return nil, p.errorf(n, "%s needs a value", kind)
+	}
+	switch kind {
+	case "thumbnail":
+		return p.thumbnail(body)
+	case "button", "link":
+		return p.button(kind, body)
+	}
+	return nil, p.errorf(n, "unknown accessory %q (expected thumbnail, button or link)", kind)
+}
+
+func (p parser) thumbnail(n *yaml.Node) (func(data any) (dmsg.Component, error), error) {
+	fields, err := p.fields(n, "thumbnail", "url", "description", "spoiler")
+	if err != nil {
+		return nil, err
+	}
+	url, err := p.requiredText(n, fields, "url", "thumbnail")
+	if err != nil {
+		return nil, err
+	}
+	description, err := p.optionalText(fields, "description")
+	if err != nil {
+		return nil, err
+	}
+	spoiler, err := p.optionalBool(fields, "spoiler")
+	if err != nil {
+		return nil, err
+	}
+	return func(data any) (dmsg.Component, error) {
+		u, err := url.render(data)
+		if err != nil {
+			return nil, err
+		}
+		var desc string
+		if description != nil {
+			if desc, err = description.render(data); err != nil {
+				return nil, err
+			}
+		}
+		var opts []dmsg.ThumbnailOption
+		if spoiler {
+			opts = append(opts, dmsg.Spoiler())
+		}
+		return dmsg.Thumbnail(u, desc, opts...), nil
+	}, nil
+}
+
+var buttonStyles = map[string]dmsg.ButtonStyle{
+	"primary":   dmsg.Primary,
+	"secondary": dmsg.Secondary,
+	"success":   dmsg.Success,
+	"danger":    dmsg.Danger,
+}
+
+func (p parser) button(kind string, n *yaml.Node) (func(data any) (dmsg.Component, error), error) {
+	target := "custom_id"
+	allowed := []string{"label", "custom_id", "style", "emoji", "disabled"}
+	if kind == "link" {
+		target = "url"
+		allowed = []string{"label", "url", "emoji", "disabled"}
+	}
+	fields, err := p.fields(n, kind, allowed...)
+	if err != nil {
+		return nil, err
+	}
+	// Buttons with an emoji and no label are icon buttons.
+	_, hasLabel := fields["label"]
+	_, hasEmoji := fields["emoji"]
+	icon := kind == "button" && !hasLabel && hasEmoji
+	var label text
+	if !icon {
+		if label, err = p.requiredText(n, fields, "label", kind); err != nil {
+			return nil, err
+		}
+	}
+	dest, err := p.requiredText(n, fields, target, kind)
+	if err != nil {
+		return nil, err
+	}
+	var static []dmsg.ButtonOption
+	if s, ok := fields["style"]; ok {
+		style, ok := buttonStyles[s.Value]
+		if !ok {
+			return nil, p.errorf(s, "unknown button style %q (expected primary, secondary, success or danger)", s.Value)
+		}
+		static = append(static, dmsg.Style(style))
+	}
+	var emoji *discordgo.ComponentEmoji
+	if e, ok := fields["emoji"]; ok {
+		if emoji, err = p.emoji(e); err != nil {
+			return nil, err
+		}
+		if !icon {
+			static = append(static, dmsg.Emoji(emoji))
+		}
+	}
+	if disabled, err := p.optionalBool(fields, "disabled"); err != nil {
+		return nil, err
+	} else if disabled {
+		static = append(static, dmsg.Disabled())
+	}
+	return func(data any) (dmsg.Component, error) {
+		d, err := dest.render(data)
+		if err != nil {
+			return nil, err
+		}
+		if icon {
+			return dmsg.IconButton(emoji, d, static...), nil
+		}
+		l, err := label.render(data)
+		if err != nil {
+			return nil, err
+		}
+		if kind == "link" {
+			return dmsg.LinkButton(l, d, static...), nil
+		}
+		return dmsg.Button(l, d, static...), nil
+	}, nil
+}
+
+// emoji accepts a string understood by dmsg.ParseEmoji, or the name of an
+// emoji in the template's registry, or a mapping with name, id and animated
+// fields
+func (p parser) emoji(n *yaml.Node) (*discordgo.ComponentEmoji, error) {
+	if n.Kind == yaml.ScalarNode {
+		parse := dmsg.ParseEmoji
+		if p.emojis != nil {
+			parse = p.emojis.Parse
+		}
+		emoji, err := parse(n.Value)
+		if err != nil {
+			return nil, p.errorf(n, "invalid emoji %q", n.Value)
+		}
+		return emoji, nil
+	}
+	fields, err := p.fields(n, "emoji", "name", "id", "animated")
+	if err != nil {
+		return nil, err
+	}
+	emoji := &discordgo.ComponentEmoji{}
+	if name, ok := fields["name"]; ok {
+		emoji.Name = name.Value
+	}
+	if id, ok := fields["id"]; ok {
+		emoji.ID = id.Value
+	}
+	if emoji.Animated, err = p.optionalBool(fields, "animated"); err != nil {
+		return nil, err
+	}
+	return emoji, nil
+}
+
+func (p parser) textDisplay(n *yaml.Node) (builder, error) {
+	t, err := p.text(n)
+	if err != nil {
+		return nil, err
+	}
+	return func(data any) (part, error) {
+		content, err := t.render(data)
+		if err != nil {
+			return part{}, err
+		}
+		td := dmsg.TextDisplay(content)
+		return part{td, td}, nil
+	}, nil
+}
+
+func (p parser) separator(n, body *yaml.Node) (builder, error) {
+	var opts []dmsg.SeparatorOption
+	if body != nil {
+		fields, err := p.fields(body, "separator", "divider", "spacing")
+		if err != nil {
+			return nil, err
+		}
+		if d, ok := fields["divider"]; ok {
+			divider, err := p.bool(d)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, dmsg.WithDivider(divider))
+		}
+		if s, ok := fields["spacing"]; ok {
+			switch s.Value {
+			case "small", "1":
+				opts = append(opts, dmsg.Spacing(discordgo.SeparatorSpacingSizeSmall))
+			case "large", "2":
+				opts = append(opts, dmsg.Spacing(discordgo.SeparatorSpacingSizeLarge))
+			default:
+				return nil, p.errorf(s, "invalid spacing %q (expected small or large)", s.Value)
+			}
+		}
+	}
+	return func(data any) (part, error) {
+		s := dmsg.Separator(opts...)
+		return part{s, s}, nil
+	}, nil
+}
+
+func (p parser) actions(n *yaml.Node) (builder, error) {
+	if n.Kind != yaml.SequenceNode {
+		return nil, p.errorf(n, "actions must be a list of buttons")
+	}
+	var buttons []func(data any) (dmsg.Component, error)
+	for _, item := range n.Content {
+		kind, body, err := p.element(item)
+		if err != nil {
+			return nil, err
+		}
+		if kind != "button" && kind != "link" {
+			return nil, p.errorf(item, "unknown action %q (expected button or link)", kind)
+		}
+		if body == nil {
+			return nil, p.errorf(item, "%s needs a value", kind)
+		}
+		b, err := p.button(kind, body)
+		if err != nil {
+			return nil, err
+		}
+		buttons = append(buttons, b)
+	}
+	return func(data any) (part, error) {
+		components := make([]dmsg.Component, 0, len(buttons))
+		for _, build := range buttons {
+			c, err := build(data)
+			if err != nil {
+				return part{}, err
+			}
+			components = append(components, c)
+		}
+		row := dmsg.ActionRow(components...)
+		return part{row, row}, nil
+	}, nil
+}
+
+func (p parser) gallery(n *yaml.Node) (builder, error) {
+	if n.Kind != yaml.SequenceNode {
+		return nil, p.errorf(n, "gallery must be a list of media")
+	}
+	type media struct {
+		url, description text
+		hasDescription   bool
+		spoiler          bool
+	}
+	var items []media
+	for _, item := range n.Content {
+		fields, err := p.fields(item, "media", "url", "description", "spoiler")
+		if err != nil {
+			return nil, err
+		}
+		var m media
+		if m.url, err = p.requiredText(item, fields, "url", "media"); err != nil {
+			return nil, err
+		}
+		if d, ok := fields["description"]; ok {
+			if m.description, err = p.text(d); err != nil {
+				return nil, err
+			}
+			m.hasDescription = true
+		}
+		if m.spoiler, err = p.optionalBool(fields, "spoiler"); err != nil {
+			return nil, err
+		}
+		items = append(items, m)
+	}
+	return func(data any) (part, error) {
+		rendered := make([]dmsg.MediaItem, 0, len(items))
+		for _, m := range items {
+			url, err := m.url.render(data)
+			if err != nil {
+				return part{}, err
+			}
+			var opts []dmsg.MediaOption
+			if m.hasDescription {
+				desc, err := m.description.render(data)
+				if err != nil {
+					return part{}, err
+				}
+				opts = append(opts, dmsg.Description(desc))
+			}
+			if m.spoiler {
+				opts = append(opts, dmsg.Spoiler())
+			}
+			rendered = append(rendered, dmsg.Media(url, opts...))
+		}
+		g := dmsg.Gallery(rendered...)
+		return part{g, g}, nil
+	}, nil
+}
+
+func (p parser) file(n *yaml.Node) (builder, error) {
+	fields, err := p.fields(n, "file", "url", "spoiler")
+	if err != nil {
+		return nil, err
+	}
+	url, err := p.requiredText(n, fields, "url", "file")
+	if err != nil {
+		return nil, err
+	}
+	spoiler, err := p.optionalBool(fields, "spoiler")
+	if err != nil {
+		return nil, err
+	}
+	return func(data any) (part, error) {
+		u, err := url.render(data)
+		if err != nil {
+			return part{}, err
+		}
+		var opts []dmsg.FileOption
+		if spoiler {
+			opts = append(opts, dmsg.Spoiler())
+		}
+		f := dmsg.File(u, opts...)
+		return part{f, f}, nil
+	}, nil
+}
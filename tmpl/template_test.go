@@ -0,0 +1,296 @@
+package tmpl
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/thomasgtaylor/dmsg"
+)
+
+const welcome = `
+ephemeral: true
+components:
+  - container:
+      accent_color: "#57F287"
+      spoiler: true
+      components:
+        - section:
+            text:
+              - "## Welcome, {{.Name}}!"
+              - "Enjoy your stay"
+            accessory:
+              thumbnail:
+                url: "{{.Avatar}}"
+                description: "{{.Name}}'s avatar"
+        - separator: {divider: false, spacing: large}
+        - text: "Rules apply"
+        - actions:
+            - button: {label: Accept, custom_id: "accept:{{.ID}}", style: success, emoji: "✅"}
+            - link: {label: Rules, url: "https://example.com/rules"}
+        - gallery:
+            - url: https://example.com/1.png
+              description: First
+            - url: https://example.com/2.png
+              spoiler: true
+        - file: {url: "attachment://rules.pdf"}
+  - separator
+  - text: "Top level {{.ID}}"
+`
+
+type member struct {
+	Name, Avatar, ID string
+}
+
+func TestParse(t *testing.T) {
+	t.Run("renders yaml document", func(t *testing.T) {
+		tmpl, err := Parse("welcome.yaml", []byte(welcome))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		response, err := tmpl.Response(member{"Ada", "https://example.com/ada.png", "42"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if response.Data.Flags&discordgo.MessageFlagsEphemeral == 0 {
+			t.Error("expected ephemeral response")
+		}
+
+		if len(response.Data.Components) != 3 {
+			t.Fatalf("expected 3 top-level components, got %d", len(response.Data.Components))
+		}
+
+		container, ok := response.Data.Components[0].(*discordgo.Container)
+		if !ok {
+			t.Fatal("expected *discordgo.Container")
+		}
+
+		if container.AccentColor == nil || *container.AccentColor != 0x57F287 {
+			t.Error("accent color not applied")
+		}
+
+		if !container.Spoiler {
+			t.Error("spoiler not applied")
+		}
+
+		if len(container.Components) != 6 {
+			t.Fatalf("expected 6 container components, got %d", len(container.Components))
+		}
+
+		section := container.Components[0].(*discordgo.Section)
+		if got := section.Components[0].(*discordgo.TextDisplay).Content; got != "## Welcome, Ada!" {
+			t.Errorf("expected rendered heading, got %q", got)
+		}
+
+		thumbnail := section.Accessory.(*discordgo.Thumbnail)
+		if thumbnail.Media.URL != "https://example.com/ada.png" || *thumbnail.Description != "Ada's avatar" {
+			t.Errorf("unexpected thumbnail %+v", thumbnail)
+		}
+
+		separator := container.Components[1].(*discordgo.Separator)
+		if *separator.Divider || *separator.Spacing != discordgo.SeparatorSpacingSizeLarge {
+			t.Error("separator options not applied")
+		}
+
+		row := container.Components[3].(*discordgo.ActionsRow)
+		accept := row.Components[0].(*discordgo.Button)
+		if accept.CustomID != "accept:42" || accept.Style != discordgo.SuccessButton || accept.Emoji.Name != "✅" {
+			t.Errorf("unexpected button %+v", accept)
+		}
+
+		rules := row.Components[1].(*discordgo.Button)
+		if rules.Style != discordgo.LinkButton || rules.URL != "https://example.com/rules" {
+			t.Errorf("unexpected link button %+v", rules)
+		}
+
+		gallery := container.Components[4].(*discordgo.MediaGallery)
+		if len(gallery.Items) != 2 || !gallery.Items[1].Spoiler {
+			t.Errorf("unexpected gallery %+v", gallery)
+		}
+
+		if _, ok := container.Components[5].(*discordgo.FileComponent); !ok {
+			t.Error("expected *discordgo.FileComponent")
+		}
+
+		text := response.Data.Components[2].(*discordgo.TextDisplay)
+		if text.Content != "Top level 42" {
+			t.Errorf("expected rendered text, got %q", text.Content)
+		}
+	})
+
+	t.Run("renders json document", func(t *testing.T) {
+		tmpl, err := Parse("hello.json", []byte(`{
+  "components": [
+    {"text": "Hello {{.}}"},
+    {"actions": [{"button": {"label": "Hi", "custom_id": "hi"}}]}
+  ]
+}`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		components, err := tmpl.Components("world")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(components) != 2 {
+			t.Fatalf("expected 2 components, got %d", len(components))
+		}
+	})
+
+	t.Run("supports custom funcs", func(t *testing.T) {
+		tmpl, err := New("funcs").Funcs(template.FuncMap{"upper": strings.ToUpper}).
+			Parse([]byte(`components: [{text: "{{upper .}}"}]`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		response, err := tmpl.Response("loud")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got := response.Data.Components[0].(*discordgo.TextDisplay).Content; got != "LOUD" {
+			t.Errorf("expected LOUD, got %q", got)
+		}
+	})
+}
+
+func TestEmojis(t *testing.T) {
+	src := []byte(`components:
+  - actions:
+      - button: {label: Pay, custom_id: pay, emoji: coin}
+      - button: {label: Party, custom_id: party, emoji: "<a:party:42>"}
+      - button: {label: Hot, custom_id: hot, emoji: ":fire:"}
+      - button: {custom_id: close, emoji: "✖"}`)
+	registry := dmsg.NewEmojiRegistry(&discordgo.Emoji{Name: "coin", ID: "7"})
+
+	tmpl, err := New("emoji.yaml").Emojis(registry).Parse(src)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	response, err := tmpl.Response(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	row := response.Data.Components[0].(*discordgo.ActionsRow)
+	want := []discordgo.ComponentEmoji{
+		{Name: "coin", ID: "7"},
+		{Name: "party", ID: "42", Animated: true},
+		{Name: "🔥"},
+	}
+	for i, w := range want {
+		if got := row.Components[i].(*discordgo.Button).Emoji; got == nil || *got != w {
+			t.Errorf("expected emoji %+v, got %+v", w, got)
+		}
+	}
+
+	data, err := json.Marshal(row.Components[3])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(string(data), `"label"`) || !strings.Contains(string(data), `"custom_id":"close"`) {
+		t.Errorf("expected icon button, got %s", data)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"syntax", "components: [", "bad.yaml:1: did not find expected node content"},
+		{"syntax on later line", "components:\n  - text: a\n\tbad: tab", "bad.yaml:2: found a tab character that violates indentation"},
+		{"missing components", "ephemeral: true", "bad.yaml:1: document has no components"},
+		{"unknown component", "components:\n  - banner: x", `bad.yaml:2: unknown component "banner"`},
+		{"unknown field", "components:\n  - container:\n      colour: 1", `bad.yaml:3: unknown field "colour" in container`},
+		{"bad template", "components:\n  - text: \"{{.Name\"", "bad.yaml:2: template: bad.yaml:1: unclosed action"},
+		{"bad style", "components:\n  - actions:\n      - button: {label: a, custom_id: b, style: blurple}", `bad.yaml:3: unknown button style "blurple"`},
+		{"nested container", "components:\n  - container:\n      components:\n        - text: hi\n        - container: {}", "bad.yaml:5: containers cannot be nested"},
+		{"bad color", "components:\n  - container: {accent_color: red}", `bad.yaml:2: invalid color "red"`},
+		{"missing label", "components:\n  - actions:\n      - link: {url: x}", "bad.yaml:3: link requires label"},
+		{"bad emoji", "components:\n  - actions:\n      - button: {label: a, custom_id: b, emoji: fire}", `bad.yaml:3: invalid emoji "fire"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse("bad.yaml", []byte(tt.src))
+			if err == nil {
+				t.Fatal("expected error")
+			}
+
+			if !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("expected error starting %q, got %q", tt.want, err.Error())
+			}
+		})
+	}
+
+	t.Run("reports syntax errors as *Error", func(t *testing.T) {
+		_, err := Parse("bad.yaml", []byte("components:\n  - text: [a"))
+
+		var tmplErr *Error
+		if !errors.As(err, &tmplErr) {
+			t.Fatalf("expected *Error, got %v", err)
+		}
+	})
+
+	t.Run("reports render errors with line", func(t *testing.T) {
+		tmpl := Must(Parse("render.yaml", []byte("components:\n  - text: \"{{.Missing}}\"")))
+
+		_, err := tmpl.Components(map[string]string{})
+
+		var tmplErr *Error
+		if !errors.As(err, &tmplErr) {
+			t.Fatalf("expected *Error, got %v", err)
+		}
+
+		if tmplErr.Line != 2 {
+			t.Errorf("expected line 2, got %d", tmplErr.Line)
+		}
+	})
+}
+
+func TestTopLevelMedia(t *testing.T) {
+	tmpl := Must(Parse("top.yaml", []byte(`components:
+  - file: {url: attachment://a.txt}
+  - gallery: [{url: "https://example.com/a.png"}]`)))
+
+	response, err := tmpl.Response(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := response.Data.Components[0].(*discordgo.FileComponent); !ok {
+		t.Error("expected *discordgo.FileComponent")
+	}
+	if _, ok := response.Data.Components[1].(*discordgo.MediaGallery); !ok {
+		t.Error("expected *discordgo.MediaGallery")
+	}
+}
+
+func TestParseFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hello.yaml")
+	if err := os.WriteFile(path, []byte("components: [{text: hi}]"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl, err := ParseFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if tmpl.Name() != "hello.yaml" {
+		t.Errorf("expected name hello.yaml, got %q", tmpl.Name())
+	}
+}
@@ -0,0 +1,187 @@
+package dmsg
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Ellipsis is appended to truncated text
+const Ellipsis = "…"
+
+type truncateOption struct {
+	limit int
+}
+
+func (o truncateOption) applyToTextDisplay(t *discordgo.TextDisplay) {
+	t.Content = TruncateMarkdown(t.Content, o.limit)
+}
+
+// Truncate trims the text display's content to at most limit characters; see
+// TruncateMarkdown
+func Truncate(limit int) TextDisplayOption {
+	return truncateOption{limit}
+}
+
+// TruncateMarkdown trims markdown text to at most limit characters. Unlike a
+// plain cut it never splits a formatting marker, closes any spans and code
+// blocks left open, and appends Ellipsis. Text within the limit is returned
+// unchanged.
+func TruncateMarkdown(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	if limit <= 0 {
+		return ""
+	}
+
+	runes := []rune(text)
+	budget := limit - utf8.RuneCountInString(Ellipsis)
+	for budget > 0 {
+		cut := safeCut(runes, budget)
+		prefix := strings.TrimRightFunc(string(runes[:cut]), unicode.IsSpace)
+		closers := openMarkers(prefix)
+		total := utf8.RuneCountInString(prefix) + utf8.RuneCountInString(Ellipsis) + utf8.RuneCountInString(closers)
+		if total <= limit {
+			return prefix + Ellipsis + closers
+		}
+		budget -= total - limit
+	}
+	return string([]rune(Ellipsis)[:min(limit, utf8.RuneCountInString(Ellipsis))])
+}
+
+// markerRunes are the characters that make up formatting markers
+const markerRunes = "*_~|`"
+
+// safeCut moves a cut position back so it does not fall inside a run of
+// marker characters such as "**" or "```"
+func safeCut(runes []rune, cut int) int {
+	for cut > 0 && cut < len(runes) &&
+		strings.ContainsRune(markerRunes, runes[cut]) && runes[cut-1] == runes[cut] {
+		cut--
+	}
+	return cut
+}
+
+// openMarkers scans text and returns the markers needed to close every span
+// and code block still open at its end, innermost first
+func openMarkers(text string) string {
+	runes := []rune(text)
+	var stack []string
+	codeBlock, inlineCode := false, false
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case codeBlock:
+			if hasRunesAt(runes, i, "```") {
+				codeBlock = false
+				i += 2
+			}
+			continue
+		case inlineCode:
+			if r == '`' {
+				inlineCode = false
+			}
+			continue
+		case r == '\\':
+			i++
+			continue
+		case hasRunesAt(runes, i, "```"):
+			codeBlock = true
+			i += 2
+			continue
+		case r == '`':
+			inlineCode = true
+			continue
+		}
+
+		marker := ""
+		for _, m := range []string{"**", "__", "~~", "||"} {
+			if hasRunesAt(runes, i, m) {
+				marker = m
+				break
+			}
+		}
+		switch {
+		case marker != "":
+			i++
+		case r == '*' && !isBullet(runes, i) && isFlanking(runes, i, lastIndex(stack, "*") >= 0):
+			marker = "*"
+		case r == '_' && isWordBoundary(runes, i):
+			marker = "_"
+		default:
+			continue
+		}
+
+		if j := lastIndex(stack, marker); j >= 0 {
+			stack = append(stack[:j], stack[j+1:]...)
+		} else {
+			stack = append(stack, marker)
+		}
+	}
+
+	var closers strings.Builder
+	if inlineCode {
+		closers.WriteString("`")
+	}
+	if codeBlock {
+		if !strings.HasSuffix(text, "\n") {
+			closers.WriteString("\n")
+		}
+		closers.WriteString("```")
+	}
+	for i := len(stack) - 1; i >= 0; i-- {
+		closers.WriteString(stack[i])
+	}
+	return closers.String()
+}
+
+func hasRunesAt(runes []rune, i int, s string) bool {
+	for _, r := range s {
+		if i >= len(runes) || runes[i] != r {
+			return false
+		}
+		i++
+	}
+	return true
+}
+
+// isBullet reports whether the asterisk at i starts a list item
+func isBullet(runes []rune, i int) bool {
+	lineStart := i == 0 || runes[i-1] == '\n'
+	return lineStart && (i+1 == len(runes) || runes[i+1] == ' ')
+}
+
+// isWordBoundary reports whether the underscore at i can open or close
+// italics, rather than sitting inside a word like snake_case
+func isWordBoundary(runes []rune, i int) bool {
+	before := i > 0 && isWordRune(runes[i-1])
+	after := i+1 < len(runes) && isWordRune(runes[i+1])
+	return !before || !after
+}
+
+// isFlanking reports whether the asterisk at i can open a span, which needs
+// text after it, or close the open one, which needs text before it. An
+// asterisk between spaces, as in "2 * 3", does neither.
+func isFlanking(runes []rune, i int, closing bool) bool {
+	if closing {
+		return i > 0 && !unicode.IsSpace(runes[i-1])
+	}
+	return i+1 < len(runes) && !unicode.IsSpace(runes[i+1])
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func lastIndex(stack []string, s string) int {
+	for i := len(stack) - 1; i >= 0; i-- {
+		if stack[i] == s {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,75 @@
+package dmsg
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateMarkdown(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		limit int
+		want  string
+	}{
+		{"returns short text unchanged", "**short**", 20, "**short**"},
+		{"cuts plain text", "hello world", 6, "hello…"},
+		{"closes bold", "**bold text here**", 12, "**bold te…**"},
+		{"closes nested spans", "**bold _italic words_**", 17, "**bold _itali…_**"},
+		{"does not split markers", "ab**bold**", 4, "ab…"},
+		{"closes code blocks", "```go\nfmt.Println(1)\nfmt.Println(2)\n```", 30, "```go\nfmt.Println(1)\nfmt.…\n```"},
+		{"closes inline code", "run `go test ./...` now", 12, "run `go te…`"},
+		{"ignores markers in code", "`**` and more text", 8, "`**` an…"},
+		{"ignores snake case", "snake_case_words here", 12, "snake_case…"},
+		{"ignores escaped markers", `\*\*literal\*\* text`, 12, `\*\*literal…`},
+		{"ignores spaced asterisks", "2 * 3 = 6 is a fact worth noting", 12, "2 * 3 = 6 i…"},
+		{"ignores bullets", "* item one\n* item two", 14, "* item one\n*…"},
+		{"closes spoilers", "||secret stuff||", 10, "||secre…||"},
+		{"trims trailing space", "word     more", 8, "word…"},
+		{"handles zero limit", "text", 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TruncateMarkdown(tt.text, tt.limit)
+
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+
+			if n := utf8.RuneCountInString(got); n > tt.limit {
+				t.Errorf("result has %d characters, limit %d", n, tt.limit)
+			}
+		})
+	}
+
+	t.Run("respects limit for long input", func(t *testing.T) {
+		text := strings.Repeat("**bold** `code` ", 500)
+
+		got := TruncateMarkdown(text, MaxTextLength)
+		if n := utf8.RuneCountInString(got); n > MaxTextLength {
+			t.Errorf("result has %d characters", n)
+		}
+	})
+}
+
+func TestTruncate(t *testing.T) {
+	t.Run("truncates text display", func(t *testing.T) {
+		text := TextDisplay("**hello world**", Truncate(9))
+
+		td := text.(textDisplayComponent)
+		if td.TextDisplay.Content != "**hell…**" {
+			t.Errorf("expected truncated content, got %q", td.TextDisplay.Content)
+		}
+	})
+
+	t.Run("leaves short text alone", func(t *testing.T) {
+		text := TextDisplay("hi", Truncate(10))
+
+		td := text.(textDisplayComponent)
+		if td.TextDisplay.Content != "hi" {
+			t.Errorf("expected unchanged content, got %q", td.TextDisplay.Content)
+		}
+	})
+}
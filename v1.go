@@ -0,0 +1,109 @@
+package dmsg
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// ErrV2Only is returned when a component that requires Components v2 is
+// given to a v1 builder
+var ErrV2Only = errors.New("component requires Components v2")
+
+// ResponseV1 creates a standard interaction response without the Components
+// v2 flag, so it can be combined with embeds. Text displays are joined into
+// the message content, separators become blank lines and action rows are
+// kept. Containers, sections, thumbnails, galleries and files return an error
+// wrapping ErrV2Only.
+func ResponseV1(components ...Component) (*discordgo.InteractionResponse, error) {
+	return responseV1(discordgo.InteractionResponseChannelMessageWithSource, 0, components)
+}
+
+// EphemeralV1 creates an ephemeral interaction response without the
+// Components v2 flag. See ResponseV1.
+func EphemeralV1(components ...Component) (*discordgo.InteractionResponse, error) {
+	return responseV1(discordgo.InteractionResponseChannelMessageWithSource, discordgo.MessageFlagsEphemeral, components)
+}
+
+// UpdateV1 creates an update message response without the Components v2
+// flag. See ResponseV1.
+func UpdateV1(components ...Component) (*discordgo.InteractionResponse, error) {
+	return responseV1(discordgo.InteractionResponseUpdateMessage, 0, components)
+}
+
+// MessageV1 creates a channel message without the Components v2 flag. See
+// ResponseV1.
+func MessageV1(components ...Component) (*discordgo.MessageSend, error) {
+	content, rows, err := buildV1(components)
+	if err != nil {
+		return nil, err
+	}
+	return &discordgo.MessageSend{
+		Content:    content,
+		Components: rows,
+	}, nil
+}
+
+func responseV1(typ discordgo.InteractionResponseType, flags discordgo.MessageFlags, components []Component) (*discordgo.InteractionResponse, error) {
+	content, rows, err := buildV1(components)
+	if err != nil {
+		return nil, err
+	}
+	return &discordgo.InteractionResponse{
+		Type: typ,
+		Data: &discordgo.InteractionResponseData{
+			Flags:      flags,
+			Content:    content,
+			Components: rows,
+		},
+	}, nil
+}
+
+// buildV1 converts top-level components into message content and v1
+// action rows
+func buildV1(components []Component) (string, []discordgo.MessageComponent, error) {
+	var lines []string
+	var rows []discordgo.MessageComponent
+	for i, c := range unwrapComponents(components) {
+		switch c := c.(type) {
+		case *discordgo.TextDisplay:
+			lines = append(lines, c.Content)
+		case *discordgo.Separator:
+			lines = append(lines, "")
+		case *discordgo.ActionsRow:
+			rows = append(rows, c)
+		default:
+			return "", nil, fmt.Errorf("dmsg: components[%d]: %s: %w", i, componentName(c), ErrV2Only)
+		}
+	}
+	content := strings.Join(lines, "\n")
+	if n := utf8.RuneCountInString(content); n > MaxContentLength {
+		return "", nil, fmt.Errorf("dmsg: content is %d characters, more than %d", n, MaxContentLength)
+	}
+	return content, rows, nil
+}
+
+var componentNames = map[discordgo.ComponentType]string{
+	discordgo.ActionsRowComponent:   "ActionRow",
+	discordgo.ButtonComponent:       "Button",
+	discordgo.SelectMenuComponent:   "SelectMenu",
+	discordgo.TextInputComponent:    "TextInput",
+	discordgo.SectionComponent:      "Section",
+	discordgo.TextDisplayComponent:  "TextDisplay",
+	discordgo.ThumbnailComponent:    "Thumbnail",
+	discordgo.MediaGalleryComponent: "Gallery",
+	discordgo.FileComponentType:     "File",
+	discordgo.SeparatorComponent:    "Separator",
+	discordgo.ContainerComponent:    "Container",
+}
+
+// componentName returns the dmsg name of a component for error messages
+func componentName(c Component) string {
+	if name, ok := componentNames[c.Type()]; ok {
+		return name
+	}
+	return fmt.Sprintf("component type %d", c.Type())
+}
@@ -0,0 +1,106 @@
+package dmsg
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestResponseV1(t *testing.T) {
+	t.Run("builds content and action rows", func(t *testing.T) {
+		response, err := ResponseV1(
+			TextDisplay("**Saved**"),
+			Separator(),
+			TextDisplay("Your settings were updated."),
+			ActionRow(Button("Undo", "undo")),
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if response.Data.Flags != 0 {
+			t.Errorf("expected no flags, got %d", response.Data.Flags)
+		}
+		if response.Data.Content != "**Saved**\n\nYour settings were updated." {
+			t.Errorf("unexpected content %q", response.Data.Content)
+		}
+		if len(response.Data.Components) != 1 {
+			t.Fatalf("expected 1 component, got %d", len(response.Data.Components))
+		}
+		if _, ok := response.Data.Components[0].(*discordgo.ActionsRow); !ok {
+			t.Error("expected *discordgo.ActionsRow")
+		}
+	})
+
+	t.Run("rejects v2 components", func(t *testing.T) {
+		_, err := ResponseV1(TextDisplay("Hi"), Container(TextDisplay("Boxed")))
+
+		if !errors.Is(err, ErrV2Only) {
+			t.Fatalf("expected ErrV2Only, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "components[1]: Container") {
+			t.Errorf("expected error to name components[1]: Container, got %q", err)
+		}
+	})
+
+	t.Run("rejects long content", func(t *testing.T) {
+		_, err := ResponseV1(TextDisplay(strings.Repeat("a", MaxContentLength+1)))
+
+		if err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestEphemeralV1(t *testing.T) {
+	response, err := EphemeralV1(TextDisplay("Only you"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if response.Data.Flags != discordgo.MessageFlagsEphemeral {
+		t.Errorf("expected flags %d, got %d", discordgo.MessageFlagsEphemeral, response.Data.Flags)
+	}
+}
+
+func TestUpdateV1(t *testing.T) {
+	response, err := UpdateV1(TextDisplay("Updated"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if response.Type != discordgo.InteractionResponseUpdateMessage {
+		t.Errorf("expected type %d, got %d", discordgo.InteractionResponseUpdateMessage, response.Type)
+	}
+}
+
+func TestMessageV1(t *testing.T) {
+	t.Run("builds content", func(t *testing.T) {
+		message, err := MessageV1(TextDisplay("Hello"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if message.Content != "Hello" || message.Flags != 0 {
+			t.Errorf("unexpected message %+v", message)
+		}
+	})
+
+	t.Run("rejects galleries", func(t *testing.T) {
+		_, err := MessageV1(Gallery(Media("https://example.com/a.png")))
+
+		if !errors.Is(err, ErrV2Only) || !strings.Contains(err.Error(), "Gallery") {
+			t.Errorf("expected ErrV2Only for Gallery, got %v", err)
+		}
+	})
+
+	t.Run("rejects sections", func(t *testing.T) {
+		_, err := MessageV1(Section(TextDisplay("Hi")))
+
+		if !errors.Is(err, ErrV2Only) {
+			t.Errorf("expected ErrV2Only, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,198 @@
+package dmsg
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// ValidationError describes a component Discord would reject
+type ValidationError struct {
+	// Path locates the component, e.g. "components[0].components[2]"
+	Path string
+	Msg  string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Path + ": " + e.Msg
+}
+
+// Validate checks a response for mistakes Discord would reject with a 400,
+// such as interactive components sharing a custom ID, buttons without a
+// label or emoji, URLs with schemes Discord does not accept (link buttons
+// allow http, https and discord; media allows http, https and attachment),
+// unknown styles and spacing, rows, sections and galleries over their
+// limits, and messages over MaxComponents or MaxTextLength. Each problem is
+// reported as a *ValidationError; several are joined with errors.Join.
+func Validate(response *discordgo.InteractionResponse) error {
+	if response == nil || response.Data == nil {
+		return nil
+	}
+	return errors.Join(validateComponents(response.Data.Components)...)
+}
+
+// Build creates a standard interaction response like Response, returning
+// the problems Validate finds instead of leaving them to surface as HTTP
+// 400s
+func Build(components ...Component) (*discordgo.InteractionResponse, error) {
+	response := Response(components...)
+	if err := Validate(response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// MustBuild is like Build but panics on error. It is intended for tests and
+// package-level messages.
+func MustBuild(components ...Component) *discordgo.InteractionResponse {
+	response, err := Build(components...)
+	if err != nil {
+		panic(err)
+	}
+	return response
+}
+
+func validateComponents(components []Component) []error {
+	v := &validator{customIDs: map[string]string{}}
+	walkComponents(components, v.check)
+
+	count, text := 0, 0
+	for _, c := range components {
+		n, t := measure(c)
+		count += n
+		text += t
+	}
+	if count > MaxComponents {
+		v.report("components", "message has %d components, over the limit of %d", count, MaxComponents)
+	}
+	if text > MaxTextLength {
+		v.report("components", "message has %d characters of text, over the limit of %d", text, MaxTextLength)
+	}
+	return v.errs
+}
+
+type validator struct {
+	errs      []error
+	customIDs map[string]string
+}
+
+func (v *validator) report(path, format string, args ...any) {
+	v.errs = append(v.errs, &ValidationError{Path: path, Msg: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) check(path string, c Component) {
+	switch c := c.(type) {
+	case *discordgo.Button:
+		v.checkButton(path, c)
+	case *discordgo.ActionsRow:
+		if n := len(c.Components); n == 0 || n > MaxActionRowButtons {
+			v.report(path, "action row has %d components, want 1 to %d", n, MaxActionRowButtons)
+		}
+	case *discordgo.Section:
+		if n := len(c.Components); n == 0 || n > MaxSectionTexts {
+			v.report(path, "section has %d text displays, want 1 to %d", n, MaxSectionTexts)
+		}
+		if c.Accessory == nil {
+			v.report(path, "section has no accessory")
+		}
+	case *discordgo.MediaGallery:
+		if n := len(c.Items); n == 0 || n > MaxGalleryItems {
+			v.report(path, "gallery has %d items, want 1 to %d", n, MaxGalleryItems)
+		}
+		for i, item := range c.Items {
+			v.checkURL(fmt.Sprintf("%s.items[%d]", path, i), "media", item.Media.URL, mediaSchemes)
+		}
+	case *discordgo.Thumbnail:
+		v.checkURL(path, "thumbnail", c.Media.URL, mediaSchemes)
+	case *discordgo.FileComponent:
+		v.checkURL(path, "file", c.File.URL, mediaSchemes)
+	case *discordgo.Separator:
+		if c.Spacing != nil && *c.Spacing != discordgo.SeparatorSpacingSizeSmall && *c.Spacing != discordgo.SeparatorSpacingSizeLarge {
+			v.report(path, "unknown separator spacing %d", *c.Spacing)
+		}
+	case *discordgo.TextDisplay:
+		if strings.TrimSpace(c.Content) == "" {
+			v.report(path, "text display is empty")
+		}
+	case *discordgo.SelectMenu:
+		v.checkCustomID(path, c.CustomID)
+	case *discordgo.TextInput:
+		v.checkCustomID(path, c.CustomID)
+	}
+}
+
+func (v *validator) checkButton(path string, b *discordgo.Button) {
+	switch b.Style {
+	case discordgo.LinkButton:
+		if b.URL == "" {
+			v.report(path, "link button has no URL")
+		} else {
+			v.checkURL(path, "link button", b.URL, linkSchemes)
+		}
+	case discordgo.PrimaryButton, discordgo.SecondaryButton, discordgo.SuccessButton, discordgo.DangerButton:
+		v.checkCustomID(path, b.CustomID)
+	case discordgo.PremiumButton:
+		if b.SKUID == "" {
+			v.report(path, "premium button has no SKU ID")
+		}
+		if b.Label != "" || b.CustomID != "" || b.URL != "" || b.Emoji != nil {
+			v.report(path, "premium button cannot have a label, custom ID, URL or emoji")
+		}
+		return
+	default:
+		v.report(path, "unknown button style %d", b.Style)
+	}
+	if b.Label == "" && b.Emoji == nil {
+		v.report(path, "button has no label or emoji")
+	} else if n := utf8.RuneCountInString(b.Label); n > MaxButtonLabelLength {
+		v.report(path, "button label is %d characters, more than %d", n, MaxButtonLabelLength)
+	}
+}
+
+// URL schemes Discord accepts for link buttons and media
+var (
+	linkSchemes  = []string{"http", "https", "discord"}
+	mediaSchemes = []string{"http", "https", "attachment"}
+)
+
+func (v *validator) checkURL(path, kind, raw string, schemes []string) {
+	u, err := url.Parse(raw)
+	switch {
+	case raw == "":
+		v.report(path, "%s has no URL", kind)
+	case err != nil:
+		v.report(path, "%s URL %q is invalid: %v", kind, raw, err)
+	case u.Scheme == "":
+		v.report(path, "%s URL %q has no scheme", kind, raw)
+	case !slices.Contains(schemes, strings.ToLower(u.Scheme)):
+		v.report(path, "%s URL %q must use %s", kind, raw, joinOr(schemes))
+	}
+}
+
+// joinOr joins words as "a, b or c"
+func joinOr(words []string) string {
+	if len(words) < 2 {
+		return strings.Join(words, "")
+	}
+	return strings.Join(words[:len(words)-1], ", ") + " or " + words[len(words)-1]
+}
+
+func (v *validator) checkCustomID(path, id string) {
+	if id == "" {
+		v.report(path, "custom ID is empty")
+		return
+	}
+	if n := utf8.RuneCountInString(id); n > MaxCustomIDLength {
+		v.report(path, "custom ID is %d characters, more than %d", n, MaxCustomIDLength)
+	}
+	if first, ok := v.customIDs[id]; ok {
+		v.report(path, "duplicate custom ID %q (also used at %s)", id, first)
+		return
+	}
+	v.customIDs[id] = path
+}
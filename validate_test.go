@@ -0,0 +1,209 @@
+package dmsg
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestValidate(t *testing.T) {
+	t.Run("accepts valid response", func(t *testing.T) {
+		response := Response(
+			Container(
+				Section(TextDisplay("Item"), Accessory(Button("Buy", "buy"))),
+				ActionRow(Button("Next", "next"), LinkButton("Docs", "https://example.com")),
+			),
+		)
+
+		if err := Validate(response); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("reports duplicate custom IDs with both paths", func(t *testing.T) {
+		response := Response(
+			Container(
+				Section(TextDisplay("Item"), Accessory(Button("Buy", "buy"))),
+				ActionRow(Button("Buy again", "buy")),
+			),
+		)
+
+		err := Validate(response)
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			t.Fatalf("expected *ValidationError, got %v", err)
+		}
+		if verr.Path != "components[0].components[1].components[0]" {
+			t.Errorf("unexpected path %s", verr.Path)
+		}
+		if !strings.Contains(verr.Msg, `"buy" (also used at components[0].components[0].accessory)`) {
+			t.Errorf("unexpected message %q", verr.Msg)
+		}
+	})
+
+	t.Run("accepts allowed schemes", func(t *testing.T) {
+		response := Response(
+			ActionRow(
+				LinkButton("Web", "https://example.com"),
+				LinkButton("Channel", "discord://-/channels/1/2"),
+			),
+			Container(
+				Section(TextDisplay("Logo"), Accessory(Thumbnail("attachment://logo.png", "Logo"))),
+				Gallery(Media("http://example.com/a.png", Description("A"))),
+				File("attachment://report.csv"),
+			),
+		)
+
+		if err := Validate(response); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("accepts icon buttons", func(t *testing.T) {
+		response := Response(ActionRow(
+			IconButton(&discordgo.ComponentEmoji{Name: "◀"}, "prev"),
+			Button("", "next", Emoji(&discordgo.ComponentEmoji{Name: "▶"})),
+		))
+
+		if err := Validate(response); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("accepts premium buttons", func(t *testing.T) {
+		if err := Validate(Response(ActionRow(PremiumButton("123")))); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("ignores link buttons", func(t *testing.T) {
+		response := Response(ActionRow(
+			LinkButton("A", "https://example.com/a"),
+			LinkButton("B", "https://example.com/b"),
+		))
+
+		if err := Validate(response); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("reports long custom IDs", func(t *testing.T) {
+		response := Response(ActionRow(Button("Go", strings.Repeat("x", MaxCustomIDLength+1))))
+
+		if err := Validate(response); err == nil {
+			t.Error("expected error")
+		}
+	})
+
+	t.Run("reports every problem", func(t *testing.T) {
+		response := Response(ActionRow(Button("A", "x"), Button("B", "x"), Button("C", "x")))
+
+		err := Validate(response)
+		joined, ok := err.(interface{ Unwrap() []error })
+		if !ok || len(joined.Unwrap()) != 2 {
+			t.Errorf("expected 2 errors, got %v", err)
+		}
+	})
+
+	problems := []struct {
+		name      string
+		component Component
+		want      string
+	}{
+		{"empty label", ActionRow(Button("", "go")), "button has no label or emoji"},
+		{"icon without emoji", ActionRow(IconButton(nil, "go")), "button has no label or emoji"},
+		{"long label", ActionRow(Button(strings.Repeat("x", MaxButtonLabelLength+1), "go")), "button label is 81 characters"},
+		{"unknown style", ActionRow(Button("Go", "go", Style(9))), "unknown button style 9"},
+		{"empty custom ID", ActionRow(Button("Go", "")), "custom ID is empty"},
+		{"relative link", ActionRow(LinkButton("Docs", "/docs")), `link button URL "/docs" has no scheme`},
+		{"file link", ActionRow(LinkButton("Docs", "file:///docs")), `link button URL "file:///docs" must use http, https or discord`},
+		{"relative thumbnail", Section(TextDisplay("Hi"), Accessory(Thumbnail("logo.png", "Logo"))), `thumbnail URL "logo.png" has no scheme`},
+		{"discord media", Container(Gallery(Media("discord://x", Description("X")))), `components[0].components[0].items[0]: media URL "discord://x" must use http, https or attachment`},
+		{"ftp file", Container(File("ftp://example.com/a.zip")), `file URL "ftp://example.com/a.zip" must use`},
+		{"missing link", ActionRow(LinkButton("Docs", "")), "link button has no URL"},
+		{"premium without SKU", ActionRow(PremiumButton("")), "premium button has no SKU ID"},
+		{"premium with custom ID", ActionRow(Button("Buy", "buy", Style(Premium))), "premium button cannot have a label, custom ID, URL or emoji"},
+		{"bad spacing", Separator(Spacing(3)), "unknown separator spacing 3"},
+		{"empty text", TextDisplay(" "), "text display is empty"},
+		{"crowded row", ActionRow(Button("1", "1"), Button("2", "2"), Button("3", "3"), Button("4", "4"), Button("5", "5"), Button("6", "6")), "action row has 6 components"},
+		{"section without accessory", Section(TextDisplay("Hi")), "section has no accessory"},
+		{"empty gallery", Container(Gallery()), "gallery has 0 items"},
+	}
+	for _, tt := range problems {
+		t.Run("reports "+tt.name, func(t *testing.T) {
+			err := Validate(Response(tt.component))
+
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	t.Run("accepts nil response", func(t *testing.T) {
+		if err := Validate(&discordgo.InteractionResponse{}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestBuild(t *testing.T) {
+	t.Run("returns valid response", func(t *testing.T) {
+		response, err := Build(TextDisplay("Hello"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if response.Data.Flags != discordgo.MessageFlagsIsComponentsV2 {
+			t.Errorf("expected flags %d, got %d", discordgo.MessageFlagsIsComponentsV2, response.Data.Flags)
+		}
+	})
+
+	t.Run("reports too many components", func(t *testing.T) {
+		components := make([]Component, 50)
+		for i := range components {
+			components[i] = TextDisplay("Line")
+		}
+
+		_, err := Build(components...)
+		if err == nil || !strings.Contains(err.Error(), "components: message has 50 components, over the limit of 40") {
+			t.Errorf("expected component limit error, got %v", err)
+		}
+	})
+
+	t.Run("reports too much text", func(t *testing.T) {
+		_, err := Build(Container(TextDisplay(strings.Repeat("x", 5000))))
+		if err == nil || !strings.Contains(err.Error(), "components: message has 5000 characters of text, over the limit of 4000") {
+			t.Errorf("expected text limit error, got %v", err)
+		}
+	})
+
+	t.Run("returns validation errors", func(t *testing.T) {
+		response, err := Build(ActionRow(Button("", "go")))
+
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		if response != nil {
+			t.Error("expected nil response")
+		}
+	})
+}
+
+func TestMustBuild(t *testing.T) {
+	t.Run("panics on invalid response", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic")
+			}
+		}()
+		MustBuild(ActionRow(Button("", "go")))
+	})
+
+	t.Run("returns valid response", func(t *testing.T) {
+		if MustBuild(TextDisplay("Hello")) == nil {
+			t.Error("expected response")
+		}
+	})
+}
@@ -0,0 +1,38 @@
+package dmsg
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// walkComponents calls fn for every component in the tree, depth first,
+// with its JSON-style path such as "components[0].components[1].accessory"
+func walkComponents(components []Component, fn func(path string, c Component)) {
+	walkChildren("components", components, fn)
+}
+
+func walkChildren(prefix string, components []Component, fn func(path string, c Component)) {
+	for i, c := range components {
+		walkComponent(fmt.Sprintf("%s[%d]", prefix, i), c, fn)
+	}
+}
+
+func walkComponent(path string, c Component, fn func(path string, c Component)) {
+	c = Unwrap(c)
+	if c == nil {
+		return
+	}
+	fn(path, c)
+	switch c := c.(type) {
+	case *discordgo.Container:
+		walkChildren(path+".components", c.Components, fn)
+	case *discordgo.Section:
+		walkChildren(path+".components", c.Components, fn)
+		if c.Accessory != nil {
+			walkComponent(path+".accessory", c.Accessory, fn)
+		}
+	case *discordgo.ActionsRow:
+		walkChildren(path+".components", c.Components, fn)
+	}
+}
@@ -0,0 +1,37 @@
+package dmsg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWalkComponents(t *testing.T) {
+	components := []Component{
+		Container(
+			Section(
+				TextDisplay("Text"),
+				Accessory(Button("Go", "go")),
+			),
+			ActionRow(Button("A", "a")),
+		),
+		TextDisplay("Footer"),
+	}
+
+	var paths []string
+	walkComponents(components, func(path string, _ Component) {
+		paths = append(paths, path)
+	})
+
+	want := []string{
+		"components[0]",
+		"components[0].components[0]",
+		"components[0].components[0].components[0]",
+		"components[0].components[0].accessory",
+		"components[0].components[1]",
+		"components[0].components[1].components[0]",
+		"components[1]",
+	}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("expected %v, got %v", want, paths)
+	}
+}
@@ -0,0 +1,130 @@
+package dmsg
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// BarStyle represents progress bar visual styles
+type BarStyle int
+
+const (
+	// BlockBar draws whole blocks: ██████░░░░
+	BlockBar BarStyle = iota
+	// SmoothBar draws eighth blocks for finer steps: ██████▍░░░
+	SmoothBar
+	// EmojiBar draws colored squares: 🟩🟩🟩⬛⬛
+	EmojiBar
+)
+
+// WidgetOption configures a ProgressBar or Sparkline
+type WidgetOption interface {
+	applyToWidget(*widget)
+}
+
+type widget struct {
+	label string
+}
+
+type labelOption struct {
+	label string
+}
+
+func (o labelOption) applyToWidget(w *widget) {
+	w.label = o.label
+}
+
+// Label shows text in bold above the widget
+func Label(text string) WidgetOption {
+	return labelOption{text}
+}
+
+func widgetText(content string, opts []WidgetOption) string {
+	w := &widget{}
+	for _, opt := range opts {
+		opt.applyToWidget(w)
+	}
+	if w.label != "" {
+		return "**" + w.label + "**\n" + content
+	}
+	return content
+}
+
+// ProgressBar creates a text display showing value out of total as a bar of
+// width cells followed by the percentage
+func ProgressBar(value, total float64, width int, style BarStyle, opts ...WidgetOption) interface {
+	Component
+	ContainerOption
+	SectionOption
+} {
+	ratio := 0.0
+	if total > 0 {
+		ratio = math.Min(math.Max(value/total, 0), 1)
+	}
+	if math.IsNaN(ratio) {
+		ratio = 0
+	}
+	bar := progressBar(ratio, width, style)
+	return TextDisplay(widgetText(fmt.Sprintf("%s %d%%", bar, int(math.Round(ratio*100))), opts))
+}
+
+var eighths = []rune("▏▎▍▌▋▊▉")
+
+func progressBar(ratio float64, width int, style BarStyle) string {
+	if width <= 0 {
+		return ""
+	}
+	switch style {
+	case EmojiBar:
+		filled := int(math.Round(ratio * float64(width)))
+		return strings.Repeat("🟩", filled) + strings.Repeat("⬛", width-filled)
+	case SmoothBar:
+		steps := int(math.Round(ratio * float64(width) * 8))
+		full, partial := steps/8, steps%8
+		bar := strings.Repeat("█", full)
+		empty := width - full
+		if partial > 0 {
+			bar += string(eighths[partial-1])
+			empty--
+		}
+		return "`" + bar + strings.Repeat("░", empty) + "`"
+	default:
+		filled := int(math.Round(ratio * float64(width)))
+		return "`" + strings.Repeat("█", filled) + strings.Repeat("░", width-filled) + "`"
+	}
+}
+
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline creates a text display showing values as a line of block
+// characters scaled between their minimum and maximum
+func Sparkline(values []float64, opts ...WidgetOption) interface {
+	Component
+	ContainerOption
+	SectionOption
+} {
+	return TextDisplay(widgetText("`"+sparkline(values)+"`", opts))
+}
+
+func sparkline(values []float64) string {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if !math.IsNaN(v) && !math.IsInf(v, 0) {
+			lo, hi = math.Min(lo, v), math.Max(hi, v)
+		}
+	}
+	var b strings.Builder
+	for _, v := range values {
+		switch {
+		case math.IsNaN(v) || math.IsInf(v, 0):
+			b.WriteRune(' ')
+		case hi == lo:
+			b.WriteRune(sparkLevels[0])
+		default:
+			level := int(math.Round((v - lo) / (hi - lo) * float64(len(sparkLevels)-1)))
+			b.WriteRune(sparkLevels[level])
+		}
+	}
+	return b.String()
+}
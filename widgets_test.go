@@ -0,0 +1,105 @@
+package dmsg
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestProgressBar(t *testing.T) {
+	content := func(c Component) string {
+		return c.(textDisplayComponent).Content
+	}
+
+	tests := []struct {
+		name  string
+		value float64
+		total float64
+		width int
+		style BarStyle
+		want  string
+	}{
+		{"block", 3, 10, 10, BlockBar, "`███░░░░░░░` 30%"},
+		{"smooth", 0.55, 1, 4, SmoothBar, "`██▎░` 55%"},
+		{"emoji", 2, 4, 4, EmojiBar, "🟩🟩⬛⬛ 50%"},
+		{"full", 10, 10, 3, BlockBar, "`███` 100%"},
+		{"clamps overflow", 15, 10, 3, BlockBar, "`███` 100%"},
+		{"clamps negative", -1, 10, 3, BlockBar, "`░░░` 0%"},
+		{"handles zero total", 5, 0, 2, BlockBar, "`░░` 0%"},
+		{"handles NaN value", math.NaN(), 10, 2, BlockBar, "`░░` 0%"},
+		{"handles infinite total", math.Inf(1), math.Inf(1), 2, SmoothBar, "`░░` 0%"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := content(ProgressBar(tt.value, tt.total, tt.width, tt.style))
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("shows label", func(t *testing.T) {
+		got := content(ProgressBar(1, 2, 2, BlockBar, Label("Quota")))
+
+		if got != "**Quota**\n`█░` 50%" {
+			t.Errorf("unexpected content %q", got)
+		}
+	})
+
+	t.Run("works in containers", func(t *testing.T) {
+		container := Container(ProgressBar(1, 2, 4, EmojiBar))
+
+		c := container.(*discordgo.Container)
+		if _, ok := c.Components[0].(*discordgo.TextDisplay); !ok {
+			t.Error("expected *discordgo.TextDisplay")
+		}
+	})
+}
+
+func TestSparkline(t *testing.T) {
+	content := func(c Component) string {
+		return c.(textDisplayComponent).Content
+	}
+
+	t.Run("scales between min and max", func(t *testing.T) {
+		got := content(Sparkline([]float64{0, 1, 2, 3, 4, 5, 6, 7}))
+
+		if got != "`▁▂▃▄▅▆▇█`" {
+			t.Errorf("unexpected content %q", got)
+		}
+	})
+
+	t.Run("handles flat series", func(t *testing.T) {
+		got := content(Sparkline([]float64{5, 5, 5}))
+
+		if got != "`▁▁▁`" {
+			t.Errorf("unexpected content %q", got)
+		}
+	})
+
+	t.Run("leaves gaps for missing values", func(t *testing.T) {
+		got := content(Sparkline([]float64{0, math.NaN(), 1}))
+
+		if got != "`▁ █`" {
+			t.Errorf("unexpected content %q", got)
+		}
+	})
+
+	t.Run("leaves gaps for infinite values", func(t *testing.T) {
+		got := content(Sparkline([]float64{1, math.Inf(1), 2, math.Inf(-1)}))
+
+		if got != "`▁ █ `" {
+			t.Errorf("unexpected content %q", got)
+		}
+	})
+
+	t.Run("shows label", func(t *testing.T) {
+		got := content(Sparkline([]float64{1, 2}, Label("Latency")))
+
+		if got != "**Latency**\n`▁█`" {
+			t.Errorf("unexpected content %q", got)
+		}
+	})
+}